@@ -2,15 +2,20 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/prisma/photon-go/prisma"
 	"github.com/prisma/photon-go/prisma/comment"
+	"github.com/prisma/photon-go/prisma/id"
 	"github.com/prisma/photon-go/prisma/post"
 	"github.com/prisma/photon-go/prisma/user"
 )
 
+// userKey is the context key of the signed in user
+type userKey struct{}
+
 func main() {
 	//
 	// https://www.prisma.io/docs/prisma-client/setup/constructor-GO-go02/
@@ -34,8 +39,8 @@ func main() {
 	usrs, err := client.User.FindMany()
 
 	// Fetch a single post by its id:
-	id := "cjsx2j8bw02920b25rl806l07"
-	usr, err = client.User.Find(user.Where().ID(id))
+	userID := "cjsx2j8bw02920b25rl806l07"
+	usr, err = client.User.Find(user.Where().ID(userID))
 
 	// Fetch a single user by their email:
 	email = "ada@prisma.io"
@@ -62,6 +67,17 @@ func main() {
 	// Fetch users called Ada or Grace:
 	usrs, err = client.User.FindMany(user.Where().NameIn("Ada", "Grace"))
 
+	// Fetch users without a name:
+	usrs, err = client.User.FindMany(user.Where().NameIsNull())
+
+	// Fetch users with a balance over 1000.50:
+	usrs, err = client.User.FindMany(user.Where().BalanceGt(prisma.MustDecimal("1000.50")))
+
+	// Fetch posts imported from another blog:
+	psts, err = client.Post.FindMany(
+		post.Where().MetadataPathEquals([]string{"source", "kind"}, prisma.JSON(`"import"`)),
+	)
+
 	christmas := time.Date(2019, time.December, 24, 10, 0, 0, 0, time.UTC)
 
 	// Fetch comments created before December 24, 2019:
@@ -82,6 +98,9 @@ func main() {
 	// Sort users alphabetically by their names (descending):
 	usrs, err = client.User.FindMany(user.Order().Name(prisma.DESC))
 
+	// Sort posts by their number of comments, then by title:
+	psts, err = client.Post.FindMany(post.Order().CommentsCount(prisma.DESC).Title(prisma.ASC))
+
 	// Fetch the first 3 posts (seeking forward):
 	psts, err = client.Post.FindMany(post.First(5))
 
@@ -120,6 +139,53 @@ func main() {
 		post.Skip(5),
 	)
 
+	// Fetch a user along with their latest posts and each post's comments:
+	usr, err = client.User.Find(
+		user.Where().Email(email),
+		user.WithPosts(
+			post.Order().CreatedAt(prisma.DESC),
+			post.First(10),
+			post.WithComments(),
+		),
+	)
+	psts = usr.Relations.Posts
+
+	// Or load a user's posts and a post's author on demand:
+	psts, err = usr.Posts(ctx, post.Where().TitleContains("prisma"))
+	usr, err = psts[0].Author(ctx)
+
+	// Fetch the distinct authors who commented this week:
+	weekAgo := time.Now().AddDate(0, 0, -7)
+	usrs, err = client.Comment.As(comment.Where().CreatedAtGt(weekAgo)).User.FindMany()
+
+	// Fetch the newest comment for each distinct text:
+	cmnts, err = client.Comment.FindMany(
+		comment.Distinct(comment.Field.Text),
+		comment.Order().CreatedAt(prisma.DESC),
+	)
+
+	// Walk through all the posts, 500 at a time:
+	it := client.Post.Iterate(ctx, post.Order().CreatedAt(prisma.ASC)).BatchSize(500)
+	for it.Next() {
+		psts = append(psts, it.Value())
+	}
+	err = it.Err()
+
+	// Export all the posts one at a time, without holding them in memory:
+	err = client.Post.FindEach(ctx, func(pst *prisma.Post) error {
+		fmt.Println(pst.ID, pst.Title)
+		return nil
+	})
+
+	// Count the posts with prisma in their title:
+	count, err := client.Post.Count(post.Where().TitleContains("prisma"))
+
+	// Total views of published and unpublished posts with more than 10 posts:
+	groups, err := client.Post.GroupBy(post.Field.Published).FindMany(
+		post.Having().CountGt(10),
+		post.GroupOrder().SumViews(prisma.DESC),
+	)
+
 	//
 	// https://www.prisma.io/docs/prisma-client/basic-data-access/writing-data-GO-go08/
 	//
@@ -155,6 +221,28 @@ func main() {
 		user.Where().ID("cjsyytzn0004d0982gbyeqep7"),
 	)
 
+	// Remove the name of an existing user:
+	usr, err = client.User.Update(
+		user.New().NameSetNull(),
+		user.Where().ID("cjsyytzn0004d0982gbyeqep7"),
+	)
+
+	// Update a post unless someone else updated it since it was read:
+	pst, err = client.Post.Update(
+		post.New().Title("Join us for GraphQL Conf"),
+		post.Where().ID(pst.ID),
+		post.IfVersion(pst.Version),
+	)
+	if err == prisma.ErrConflict {
+		fmt.Println("the post was changed, reload it and try again")
+	}
+
+	// Count a view of a post:
+	pst, err = client.Post.Update(
+		post.New().ViewsIncrement(1),
+		post.Where().ID("cjsyqxwqv000l0982p5qdq34p"),
+	)
+
 	// Update the author of a post:
 	pst, err = client.Post.Update(
 		post.New().ConnectAuthor(
@@ -197,6 +285,12 @@ func main() {
 		post.Where().CreatedAtGt(christmas),
 	)
 
+	// Create users in bulk, skipping the ones that already exist:
+	created, err := client.User.CreateMany(ctx, []*prisma.UserInput{
+		user.New().Email("ada@prisma.io").Name("Ada"),
+		user.New().Email("grace@prisma.io").Name("Grace"),
+	}, &prisma.CreateManyOptions{SkipDuplicates: true})
+
 	// Nested Object Writes
 	usr, err = client.User.Create(
 		user.New().Email("bob@prisma.io").Name("Bob").
@@ -209,6 +303,75 @@ func main() {
 			),
 	)
 
+	// Claim up to 10 unpublished posts that no other worker has claimed:
+	err = client.Transaction(ctx, func(tx *prisma.Client) error {
+		psts, err := tx.Post.SkipLocked().FindForUpdate(
+			post.Where().Published(false),
+			post.First(10),
+		)
+		if err != nil {
+			return err
+		}
+		for _, pst := range psts {
+			if _, err := tx.Post.Update(post.New().Published(true), post.Where().ID(pst.ID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	// Report every invalid field before anything is sent to the engine
+	_, err = client.User.Create(user.New().Name("Ada").Email("ada"))
+	if invalid, ok := err.(*prisma.ValidationError); ok {
+		for _, f := range invalid.Fields {
+			fmt.Println(f.Field, f.Message)
+		}
+	}
+
+	// Scope every query to the tenant of its context
+	tenants := client.WithTenancy(prisma.Tenancy{
+		Fields: map[string]string{"User": "tenantId", "Post": "tenantId", "Comment": "tenantId"},
+	})
+	_, err = tenants.WithContext(prisma.WithTenant(ctx, "acme")).Post.FindMany()
+
+	// Only let users see, edit and delete their own posts
+	client.Post.Policy(func(ctx context.Context) (*prisma.PostWhere, error) {
+		userID, ok := ctx.Value(userKey{}).(string)
+		if !ok {
+			return nil, errors.New("not signed in")
+		}
+		return post.Where().AuthorID(userID), nil
+	})
+	_, err = client.WithContext(context.WithValue(ctx, userKey{}, "ada")).Post.FindMany()
+
+	// Count every post, including the deleted ones
+	_, err = client.Post.Count(post.WithDeleted())
+
+	// Create a post for a user whose ID is known before it's created
+	authorID := id.CUID()
+	_, err = client.User.Create(user.New().ID(authorID).Email("ada@prisma.io"))
+	_, err = client.Post.Create(post.New().Title("Hello").ConnectAuthor(user.Connect().ID(authorID)))
+	joined, err := id.CUIDTime(authorID)
+	fmt.Println(joined)
+
+	// Create a post with timestamps from a fixed clock
+	frozen := client.WithClock(prisma.ClockFunc(func() time.Time {
+		return christmas
+	}))
+	_, err = frozen.Post.Create(post.New().Title("Merry Christmas"))
+
+	// Keep admins from being deleted
+	client.User.BeforeDelete(func(tx *prisma.Client, where *prisma.UserWhere) error {
+		usr, err := tx.User.Find(where)
+		if err != nil {
+			return err
+		}
+		if usr.Role == prisma.UserRoleAdmin {
+			return fmt.Errorf("%s is an admin", usr.Email)
+		}
+		return nil
+	})
+
 	// Select API
 	// Type-safe by using the generated fields
 	var u struct {
@@ -232,6 +395,17 @@ func main() {
 		),
 	)
 
+	// Select published posts with their number of comments since christmas
+	var counted []struct {
+		post.ID
+		post.Title
+		post.CommentsCount
+	}
+	err = client.Post.Select(&counted,
+		post.Where().Published(true),
+		post.WithCommentsCount(comment.Where().CreatedAtGt(christmas)),
+	)
+
 	// Raw fields
 	// Reuses the generated fields
 	sql := fmt.Sprintf(`select %s, %s from users`,
@@ -239,5 +413,5 @@ func main() {
 		user.Email("mueller@prisma.io"),
 	)
 
-	_, _, _, _, _, _, _, _, _ = client, err, usrs, psts, usr, cmnts, updated, deleted, sql
+	_, _, _, _, _, _, _, _, _, _, _, _ = client, err, usrs, psts, usr, cmnts, updated, deleted, created, sql, count, groups
 }
@@ -0,0 +1,873 @@
+package prisma
+
+import "time"
+
+//
+// User aggregates
+//
+
+// UserField is a scalar field of a user
+type UserField string
+
+func (f UserField) enum() string {
+	return string(f)
+}
+
+// UserAggregate is the result of aggregating users. Apart from the count,
+// the aggregates of a field are null when no user has a value for it, like
+// in an empty set.
+type UserAggregate struct {
+	Count int64   `json:"count"`
+	Sum   UserSum `json:"sum"`
+	Avg   UserAvg `json:"avg"`
+	Min   UserMin `json:"min"`
+	Max   UserMax `json:"max"`
+}
+
+// UserSum of the numeric fields of users
+type UserSum struct {
+	Balance NullDecimal `json:"balance"`
+}
+
+// UserAvg of the numeric fields of users
+type UserAvg struct {
+	Balance NullDecimal `json:"balance"`
+}
+
+// UserMin of the numeric and DateTime fields of users
+type UserMin struct {
+	Balance NullDecimal `json:"balance"`
+}
+
+// UserMax of the numeric and DateTime fields of users
+type UserMax struct {
+	Balance NullDecimal `json:"balance"`
+}
+
+// userAggregates are selected when aggregating users
+const userAggregates = "count sum { balance } avg { balance } min { balance } max { balance }"
+
+// Aggregate the users matching the conditions
+func (u *UserModel) Aggregate(where ...*UserWhere) (*UserAggregate, error) {
+	op := &operation{
+		name:      "aggregateUser",
+		args:      whereArgs(u.scope, andUserWhere(where)),
+		selection: userAggregates,
+	}
+	var result UserAggregate
+	if err := u.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GroupBy groups the users by fields
+func (u *UserModel) GroupBy(by ...UserField) *UserGroupBy {
+	return &UserGroupBy{model: u, by: by}
+}
+
+// UserGroupBy is a chaining element for grouping users
+type UserGroupBy struct {
+	model *UserModel
+	by    []UserField
+}
+
+// FindMany groups matching the conditions
+func (g *UserGroupBy) FindMany(conditions ...UserGroupCondition) ([]*UserGroup, error) {
+	var c userGroupCondition
+	for _, cond := range conditions {
+		c.merge(cond.groupCondition())
+	}
+	args := whereArgs(g.model.scope, c.where).set("by", g.by)
+	if len(c.having) > 0 {
+		args = args.set("having", c.having)
+	}
+	if len(c.orderBy) > 0 {
+		args = args.set("orderBy", orderList(c.orderBy))
+	}
+	selection := ""
+	for _, field := range g.by {
+		selection += string(field) + " "
+	}
+	selection += userAggregates
+	op := &operation{
+		name:      "groupByUser",
+		args:      args,
+		selection: selection,
+	}
+	var groups []*UserGroup
+	if err := g.model.client.send(op, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// UserGroup is a group of users. Only the fields that were grouped by are set.
+type UserGroup struct {
+	ID      string     `json:"id"`
+	Name    NullString `json:"name"`
+	Email   string     `json:"email"`
+	Role    UserRole   `json:"role"`
+	Balance Decimal    `json:"balance"`
+	Avatar  []byte     `json:"avatar"`
+
+	UserAggregate
+}
+
+// UserGroupCondition interface
+type UserGroupCondition interface {
+	groupCondition() *userGroupCondition
+}
+
+// contains user group condition state
+type userGroupCondition struct {
+	where   object
+	having  object
+	orderBy object
+}
+
+func (c *userGroupCondition) merge(o *userGroupCondition) {
+	c.where = and(c.where, o.where)
+	c.having = merge(c.having, o.having)
+	c.orderBy = merge(c.orderBy, o.orderBy)
+}
+
+var _ UserGroupCondition = (*UserWhere)(nil)
+
+// groupCondition filters the users before they're grouped
+func (w *UserWhere) groupCondition() *userGroupCondition {
+	return &userGroupCondition{where: w.filter()}
+}
+
+// UserHaving filters groups by their aggregates
+type UserHaving struct {
+	f object
+}
+
+var _ UserGroupCondition = (*UserHaving)(nil)
+
+// CountGt where the group has more than n users
+func (h *UserHaving) CountGt(n int64) *UserHaving {
+	out := *h
+	out.f = out.f.set("count_gt", n)
+	return &out
+}
+
+// CountLt where the group has fewer than n users
+func (h *UserHaving) CountLt(n int64) *UserHaving {
+	out := *h
+	out.f = out.f.set("count_lt", n)
+	return &out
+}
+
+// SumBalanceGt where the sum of balance is greater than v
+func (h *UserHaving) SumBalanceGt(v Decimal) *UserHaving {
+	out := *h
+	out.f = out.f.set("balance_sum_gt", v)
+	return &out
+}
+
+// SumBalanceLt where the sum of balance is less than v
+func (h *UserHaving) SumBalanceLt(v Decimal) *UserHaving {
+	out := *h
+	out.f = out.f.set("balance_sum_lt", v)
+	return &out
+}
+
+// AvgBalanceGt where the average of balance is greater than v
+func (h *UserHaving) AvgBalanceGt(v Decimal) *UserHaving {
+	out := *h
+	out.f = out.f.set("balance_avg_gt", v)
+	return &out
+}
+
+// AvgBalanceLt where the average of balance is less than v
+func (h *UserHaving) AvgBalanceLt(v Decimal) *UserHaving {
+	out := *h
+	out.f = out.f.set("balance_avg_lt", v)
+	return &out
+}
+
+func (h *UserHaving) groupCondition() *userGroupCondition {
+	return &userGroupCondition{having: h.f}
+}
+
+// UserGroupOrder orders groups by their fields or aggregates
+type UserGroupOrder struct {
+	o object
+}
+
+var _ UserGroupCondition = (*UserGroupOrder)(nil)
+
+// Count orders by the number of users in the group
+func (g *UserGroupOrder) Count(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("count", order)
+	return &out
+}
+
+// ID orders by the grouped id
+func (g *UserGroupOrder) ID(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("id", order)
+	return &out
+}
+
+// Name orders by the grouped name
+func (g *UserGroupOrder) Name(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("name", order)
+	return &out
+}
+
+// Email orders by the grouped email
+func (g *UserGroupOrder) Email(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("email", order)
+	return &out
+}
+
+// Role orders by the grouped role
+func (g *UserGroupOrder) Role(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("role", order)
+	return &out
+}
+
+// Balance orders by the grouped balance
+func (g *UserGroupOrder) Balance(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("balance", order)
+	return &out
+}
+
+// SumBalance orders by the sum of balance
+func (g *UserGroupOrder) SumBalance(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("balance_sum", order)
+	return &out
+}
+
+// AvgBalance orders by the average of balance
+func (g *UserGroupOrder) AvgBalance(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("balance_avg", order)
+	return &out
+}
+
+// MinBalance orders by the minimum balance
+func (g *UserGroupOrder) MinBalance(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("balance_min", order)
+	return &out
+}
+
+// MaxBalance orders by the maximum balance
+func (g *UserGroupOrder) MaxBalance(order OrderBy) *UserGroupOrder {
+	out := *g
+	out.o = out.o.set("balance_max", order)
+	return &out
+}
+
+func (g *UserGroupOrder) groupCondition() *userGroupCondition {
+	return &userGroupCondition{orderBy: g.o}
+}
+
+//
+// Post aggregates
+//
+
+// PostField is a scalar field of a post
+type PostField string
+
+func (f PostField) enum() string {
+	return string(f)
+}
+
+// PostAggregate is the result of aggregating posts. Apart from the count,
+// the aggregates of a field are null when no post has a value for it, like
+// in an empty set.
+type PostAggregate struct {
+	Count int64   `json:"count"`
+	Sum   PostSum `json:"sum"`
+	Avg   PostAvg `json:"avg"`
+	Min   PostMin `json:"min"`
+	Max   PostMax `json:"max"`
+}
+
+// PostSum of the numeric fields of posts
+type PostSum struct {
+	Views       NullInt    `json:"views"`
+	Impressions NullBigInt `json:"impressions"`
+}
+
+// PostAvg of the numeric fields of posts
+type PostAvg struct {
+	Views       NullFloat `json:"views"`
+	Impressions NullFloat `json:"impressions"`
+}
+
+// PostMin of the numeric and DateTime fields of posts
+type PostMin struct {
+	Views       NullInt    `json:"views"`
+	Impressions NullBigInt `json:"impressions"`
+	CreatedAt   NullTime   `json:"createdAt"`
+	UpdatedAt   NullTime   `json:"updatedAt"`
+}
+
+// PostMax of the numeric and DateTime fields of posts
+type PostMax struct {
+	Views       NullInt    `json:"views"`
+	Impressions NullBigInt `json:"impressions"`
+	CreatedAt   NullTime   `json:"createdAt"`
+	UpdatedAt   NullTime   `json:"updatedAt"`
+}
+
+// postAggregates are selected when aggregating posts
+const postAggregates = "count sum { views impressions } avg { views impressions } min { views impressions createdAt updatedAt } max { views impressions createdAt updatedAt }"
+
+// Aggregate the posts matching the conditions
+func (p *PostModel) Aggregate(where ...*PostWhere) (*PostAggregate, error) {
+	op := &operation{
+		name:      "aggregatePost",
+		args:      whereArgs(p.live(postDeleted(where)), andPostWhere(where)),
+		selection: postAggregates,
+	}
+	var result PostAggregate
+	if err := p.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GroupBy groups the posts by fields
+func (p *PostModel) GroupBy(by ...PostField) *PostGroupBy {
+	return &PostGroupBy{model: p, by: by}
+}
+
+// PostGroupBy is a chaining element for grouping posts
+type PostGroupBy struct {
+	model *PostModel
+	by    []PostField
+}
+
+// FindMany groups matching the conditions
+func (g *PostGroupBy) FindMany(conditions ...PostGroupCondition) ([]*PostGroup, error) {
+	var c postGroupCondition
+	for _, cond := range conditions {
+		c.merge(cond.groupCondition())
+	}
+	args := whereArgs(g.model.live(c.deleted), c.where).set("by", g.by)
+	if len(c.having) > 0 {
+		args = args.set("having", c.having)
+	}
+	if len(c.orderBy) > 0 {
+		args = args.set("orderBy", orderList(c.orderBy))
+	}
+	selection := ""
+	for _, field := range g.by {
+		selection += string(field) + " "
+	}
+	selection += postAggregates
+	op := &operation{
+		name:      "groupByPost",
+		args:      args,
+		selection: selection,
+	}
+	var groups []*PostGroup
+	if err := g.model.client.send(op, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// PostGroup is a group of posts. Only the fields that were grouped by are set.
+type PostGroup struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Title       string    `json:"title"`
+	Published   bool      `json:"published"`
+	Views       int       `json:"views"`
+	Impressions BigInt    `json:"impressions"`
+	Metadata    JSON      `json:"metadata"`
+	Version     int       `json:"version"`
+	DeletedAt   NullTime  `json:"deletedAt"`
+
+	PostAggregate
+}
+
+// PostGroupCondition interface
+type PostGroupCondition interface {
+	groupCondition() *postGroupCondition
+}
+
+// contains post group condition state
+type postGroupCondition struct {
+	where   object
+	having  object
+	orderBy object
+	deleted deletedScope
+}
+
+func (c *postGroupCondition) merge(o *postGroupCondition) {
+	c.where = and(c.where, o.where)
+	c.having = merge(c.having, o.having)
+	c.orderBy = merge(c.orderBy, o.orderBy)
+	if o.deleted != excludeDeleted {
+		c.deleted = o.deleted
+	}
+}
+
+var _ PostGroupCondition = (*PostWhere)(nil)
+
+// groupCondition filters the posts before they're grouped
+func (w *PostWhere) groupCondition() *postGroupCondition {
+	return &postGroupCondition{where: w.filter(), deleted: w.deleted}
+}
+
+// PostHaving filters groups by their aggregates
+type PostHaving struct {
+	f object
+}
+
+var _ PostGroupCondition = (*PostHaving)(nil)
+
+// CountGt where the group has more than n posts
+func (h *PostHaving) CountGt(n int64) *PostHaving {
+	out := *h
+	out.f = out.f.set("count_gt", n)
+	return &out
+}
+
+// CountLt where the group has fewer than n posts
+func (h *PostHaving) CountLt(n int64) *PostHaving {
+	out := *h
+	out.f = out.f.set("count_lt", n)
+	return &out
+}
+
+// SumViewsGt where the sum of views is greater than v
+func (h *PostHaving) SumViewsGt(v int) *PostHaving {
+	out := *h
+	out.f = out.f.set("views_sum_gt", v)
+	return &out
+}
+
+// SumViewsLt where the sum of views is less than v
+func (h *PostHaving) SumViewsLt(v int) *PostHaving {
+	out := *h
+	out.f = out.f.set("views_sum_lt", v)
+	return &out
+}
+
+// AvgViewsGt where the average of views is greater than v
+func (h *PostHaving) AvgViewsGt(v float64) *PostHaving {
+	out := *h
+	out.f = out.f.set("views_avg_gt", v)
+	return &out
+}
+
+// AvgViewsLt where the average of views is less than v
+func (h *PostHaving) AvgViewsLt(v float64) *PostHaving {
+	out := *h
+	out.f = out.f.set("views_avg_lt", v)
+	return &out
+}
+
+// SumImpressionsGt where the sum of impressions is greater than v
+func (h *PostHaving) SumImpressionsGt(v BigInt) *PostHaving {
+	out := *h
+	out.f = out.f.set("impressions_sum_gt", v)
+	return &out
+}
+
+// SumImpressionsLt where the sum of impressions is less than v
+func (h *PostHaving) SumImpressionsLt(v BigInt) *PostHaving {
+	out := *h
+	out.f = out.f.set("impressions_sum_lt", v)
+	return &out
+}
+
+// AvgImpressionsGt where the average of impressions is greater than v
+func (h *PostHaving) AvgImpressionsGt(v float64) *PostHaving {
+	out := *h
+	out.f = out.f.set("impressions_avg_gt", v)
+	return &out
+}
+
+// AvgImpressionsLt where the average of impressions is less than v
+func (h *PostHaving) AvgImpressionsLt(v float64) *PostHaving {
+	out := *h
+	out.f = out.f.set("impressions_avg_lt", v)
+	return &out
+}
+
+func (h *PostHaving) groupCondition() *postGroupCondition {
+	return &postGroupCondition{having: h.f}
+}
+
+// PostGroupOrder orders groups by their fields or aggregates
+type PostGroupOrder struct {
+	o object
+}
+
+var _ PostGroupCondition = (*PostGroupOrder)(nil)
+
+// Count orders by the number of posts in the group
+func (g *PostGroupOrder) Count(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("count", order)
+	return &out
+}
+
+// ID orders by the grouped id
+func (g *PostGroupOrder) ID(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("id", order)
+	return &out
+}
+
+// CreatedAt orders by the grouped createdAt
+func (g *PostGroupOrder) CreatedAt(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("createdAt", order)
+	return &out
+}
+
+// UpdatedAt orders by the grouped updatedAt
+func (g *PostGroupOrder) UpdatedAt(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("updatedAt", order)
+	return &out
+}
+
+// Title orders by the grouped title
+func (g *PostGroupOrder) Title(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("title", order)
+	return &out
+}
+
+// Published orders by the grouped published
+func (g *PostGroupOrder) Published(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("published", order)
+	return &out
+}
+
+// Views orders by the grouped views
+func (g *PostGroupOrder) Views(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("views", order)
+	return &out
+}
+
+// Impressions orders by the grouped impressions
+func (g *PostGroupOrder) Impressions(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("impressions", order)
+	return &out
+}
+
+// Version orders by the grouped version
+func (g *PostGroupOrder) Version(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("version", order)
+	return &out
+}
+
+// DeletedAt orders by the grouped deletedAt
+func (g *PostGroupOrder) DeletedAt(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("deletedAt", order)
+	return &out
+}
+
+// SumViews orders by the sum of views
+func (g *PostGroupOrder) SumViews(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("views_sum", order)
+	return &out
+}
+
+// AvgViews orders by the average of views
+func (g *PostGroupOrder) AvgViews(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("views_avg", order)
+	return &out
+}
+
+// SumImpressions orders by the sum of impressions
+func (g *PostGroupOrder) SumImpressions(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("impressions_sum", order)
+	return &out
+}
+
+// AvgImpressions orders by the average of impressions
+func (g *PostGroupOrder) AvgImpressions(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("impressions_avg", order)
+	return &out
+}
+
+// MinViews orders by the minimum views
+func (g *PostGroupOrder) MinViews(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("views_min", order)
+	return &out
+}
+
+// MaxViews orders by the maximum views
+func (g *PostGroupOrder) MaxViews(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("views_max", order)
+	return &out
+}
+
+// MinImpressions orders by the minimum impressions
+func (g *PostGroupOrder) MinImpressions(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("impressions_min", order)
+	return &out
+}
+
+// MaxImpressions orders by the maximum impressions
+func (g *PostGroupOrder) MaxImpressions(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("impressions_max", order)
+	return &out
+}
+
+// MinCreatedAt orders by the minimum createdAt
+func (g *PostGroupOrder) MinCreatedAt(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("createdAt_min", order)
+	return &out
+}
+
+// MaxCreatedAt orders by the maximum createdAt
+func (g *PostGroupOrder) MaxCreatedAt(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("createdAt_max", order)
+	return &out
+}
+
+// MinUpdatedAt orders by the minimum updatedAt
+func (g *PostGroupOrder) MinUpdatedAt(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("updatedAt_min", order)
+	return &out
+}
+
+// MaxUpdatedAt orders by the maximum updatedAt
+func (g *PostGroupOrder) MaxUpdatedAt(order OrderBy) *PostGroupOrder {
+	out := *g
+	out.o = out.o.set("updatedAt_max", order)
+	return &out
+}
+
+func (g *PostGroupOrder) groupCondition() *postGroupCondition {
+	return &postGroupCondition{orderBy: g.o}
+}
+
+//
+// Comment aggregates
+//
+
+// CommentField is a scalar field of a comment
+type CommentField string
+
+func (f CommentField) enum() string {
+	return string(f)
+}
+
+// CommentAggregate is the result of aggregating comments. Apart from the count,
+// the aggregates of a field are null when no comment has a value for it, like
+// in an empty set.
+type CommentAggregate struct {
+	Count int64      `json:"count"`
+	Min   CommentMin `json:"min"`
+	Max   CommentMax `json:"max"`
+}
+
+// CommentMin of the numeric and DateTime fields of comments
+type CommentMin struct {
+	CreatedAt NullTime `json:"createdAt"`
+}
+
+// CommentMax of the numeric and DateTime fields of comments
+type CommentMax struct {
+	CreatedAt NullTime `json:"createdAt"`
+}
+
+// commentAggregates are selected when aggregating comments
+const commentAggregates = "count min { createdAt } max { createdAt }"
+
+// Aggregate the comments matching the conditions
+func (c *CommentModel) Aggregate(where ...*CommentWhere) (*CommentAggregate, error) {
+	op := &operation{
+		name:      "aggregateComment",
+		args:      whereArgs(c.scope, andCommentWhere(where)),
+		selection: commentAggregates,
+	}
+	var result CommentAggregate
+	if err := c.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GroupBy groups the comments by fields
+func (c *CommentModel) GroupBy(by ...CommentField) *CommentGroupBy {
+	return &CommentGroupBy{model: c, by: by}
+}
+
+// CommentGroupBy is a chaining element for grouping comments
+type CommentGroupBy struct {
+	model *CommentModel
+	by    []CommentField
+}
+
+// FindMany groups matching the conditions
+func (g *CommentGroupBy) FindMany(conditions ...CommentGroupCondition) ([]*CommentGroup, error) {
+	var c commentGroupCondition
+	for _, cond := range conditions {
+		c.merge(cond.groupCondition())
+	}
+	args := whereArgs(g.model.scope, c.where).set("by", g.by)
+	if len(c.having) > 0 {
+		args = args.set("having", c.having)
+	}
+	if len(c.orderBy) > 0 {
+		args = args.set("orderBy", orderList(c.orderBy))
+	}
+	selection := ""
+	for _, field := range g.by {
+		selection += string(field) + " "
+	}
+	selection += commentAggregates
+	op := &operation{
+		name:      "groupByComment",
+		args:      args,
+		selection: selection,
+	}
+	var groups []*CommentGroup
+	if err := g.model.client.send(op, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// CommentGroup is a group of comments. Only the fields that were grouped by are set.
+type CommentGroup struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Text      string    `json:"text"`
+
+	CommentAggregate
+}
+
+// CommentGroupCondition interface
+type CommentGroupCondition interface {
+	groupCondition() *commentGroupCondition
+}
+
+// contains comment group condition state
+type commentGroupCondition struct {
+	where   object
+	having  object
+	orderBy object
+}
+
+func (c *commentGroupCondition) merge(o *commentGroupCondition) {
+	c.where = and(c.where, o.where)
+	c.having = merge(c.having, o.having)
+	c.orderBy = merge(c.orderBy, o.orderBy)
+}
+
+var _ CommentGroupCondition = (*CommentWhere)(nil)
+
+// groupCondition filters the comments before they're grouped
+func (w *CommentWhere) groupCondition() *commentGroupCondition {
+	return &commentGroupCondition{where: w.filter()}
+}
+
+// CommentHaving filters groups by their aggregates
+type CommentHaving struct {
+	f object
+}
+
+var _ CommentGroupCondition = (*CommentHaving)(nil)
+
+// CountGt where the group has more than n comments
+func (h *CommentHaving) CountGt(n int64) *CommentHaving {
+	out := *h
+	out.f = out.f.set("count_gt", n)
+	return &out
+}
+
+// CountLt where the group has fewer than n comments
+func (h *CommentHaving) CountLt(n int64) *CommentHaving {
+	out := *h
+	out.f = out.f.set("count_lt", n)
+	return &out
+}
+
+func (h *CommentHaving) groupCondition() *commentGroupCondition {
+	return &commentGroupCondition{having: h.f}
+}
+
+// CommentGroupOrder orders groups by their fields or aggregates
+type CommentGroupOrder struct {
+	o object
+}
+
+var _ CommentGroupCondition = (*CommentGroupOrder)(nil)
+
+// Count orders by the number of comments in the group
+func (g *CommentGroupOrder) Count(order OrderBy) *CommentGroupOrder {
+	out := *g
+	out.o = out.o.set("count", order)
+	return &out
+}
+
+// ID orders by the grouped id
+func (g *CommentGroupOrder) ID(order OrderBy) *CommentGroupOrder {
+	out := *g
+	out.o = out.o.set("id", order)
+	return &out
+}
+
+// CreatedAt orders by the grouped createdAt
+func (g *CommentGroupOrder) CreatedAt(order OrderBy) *CommentGroupOrder {
+	out := *g
+	out.o = out.o.set("createdAt", order)
+	return &out
+}
+
+// Text orders by the grouped text
+func (g *CommentGroupOrder) Text(order OrderBy) *CommentGroupOrder {
+	out := *g
+	out.o = out.o.set("text", order)
+	return &out
+}
+
+// MinCreatedAt orders by the minimum createdAt
+func (g *CommentGroupOrder) MinCreatedAt(order OrderBy) *CommentGroupOrder {
+	out := *g
+	out.o = out.o.set("createdAt_min", order)
+	return &out
+}
+
+// MaxCreatedAt orders by the maximum createdAt
+func (g *CommentGroupOrder) MaxCreatedAt(order OrderBy) *CommentGroupOrder {
+	out := *g
+	out.o = out.o.set("createdAt_max", order)
+	return &out
+}
+
+func (g *CommentGroupOrder) groupCondition() *commentGroupCondition {
+	return &commentGroupCondition{orderBy: g.o}
+}
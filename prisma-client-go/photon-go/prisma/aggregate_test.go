@@ -0,0 +1,78 @@
+package prisma
+
+import (
+	"strings"
+	"testing"
+)
+
+// the engine's aggregates of an empty set, only the count isn't null
+const emptyPostAggregates = `"count": 0,
+	"sum": {"views": null, "impressions": null},
+	"avg": {"views": null, "impressions": null},
+	"min": {"views": null, "impressions": null, "createdAt": null, "updatedAt": null},
+	"max": {"views": null, "impressions": null, "createdAt": null, "updatedAt": null}`
+
+func TestAggregateEmptySet(t *testing.T) {
+	client, _ := testClient(func(string) string {
+		return `{` + emptyPostAggregates + `}`
+	})
+	aggr, err := client.Post.Aggregate((&PostWhere{}).TitleContains("nomatch"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aggr.Count != 0 {
+		t.Fatalf("expected a count of 0, got %d", aggr.Count)
+	}
+	if aggr.Sum.Views.Valid || aggr.Sum.Impressions.Valid || aggr.Avg.Impressions.Valid ||
+		aggr.Min.Impressions.Valid || aggr.Max.CreatedAt.Valid {
+		t.Fatalf("expected null aggregates, got %+v", aggr)
+	}
+}
+
+func TestAggregateDecimalEmptySet(t *testing.T) {
+	client, _ := testClient(func(string) string {
+		return `{"count": 0, "sum": {"balance": null}, "avg": {"balance": null}, "min": {"balance": null}, "max": {"balance": null}}`
+	})
+	aggr, err := client.User.Aggregate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aggr.Sum.Balance.Valid || aggr.Avg.Balance.Valid || aggr.Min.Balance.Valid || aggr.Max.Balance.Valid {
+		t.Fatalf("expected null aggregates, got %+v", aggr)
+	}
+}
+
+func TestAggregateValues(t *testing.T) {
+	client, _ := testClient(func(string) string {
+		return `{"count": 2, "sum": {"balance": "10.50"}, "avg": {"balance": "5.25"}, "min": {"balance": "0.50"}, "max": {"balance": "10"}}`
+	})
+	aggr, err := client.User.Aggregate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !aggr.Sum.Balance.Valid || aggr.Sum.Balance.Decimal.String() != "10.50" {
+		t.Fatalf("expected a sum of 10.50, got %+v", aggr.Sum.Balance)
+	}
+	if aggr.Avg.Balance.Decimal.String() != "5.25" {
+		t.Fatalf("expected an average of 5.25, got %+v", aggr.Avg.Balance)
+	}
+}
+
+func TestGroupByEmptyAggregates(t *testing.T) {
+	client, db := testClient(func(string) string {
+		return `[{"published": false, ` + emptyPostAggregates + `}]`
+	})
+	groups, err := client.Post.GroupBy(PostField("published")).FindMany()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	if groups[0].Sum.Impressions.Valid || groups[0].Max.UpdatedAt.Valid {
+		t.Fatalf("expected null aggregates, got %+v", groups[0].PostAggregate)
+	}
+	if query := db.sent()[0]; !strings.Contains(query, "groupByPost(") {
+		t.Fatalf("expected a groupBy query, got %s", query)
+	}
+}
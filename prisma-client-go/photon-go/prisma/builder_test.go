@@ -0,0 +1,164 @@
+package prisma_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prisma/specs/photongo/photon-go/prisma"
+	"github.com/prisma/specs/photongo/photon-go/prisma/post"
+	"github.com/prisma/specs/photongo/photon-go/prisma/user"
+)
+
+// engine records the queries sent to a client. It answers them with
+// respond, or with an empty list for queries and a record for mutations
+// when respond is nil.
+type engine struct {
+	mu      sync.Mutex
+	queries []string
+	respond func(query string) string
+}
+
+func (e *engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query string `json:"query"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	e.mu.Lock()
+	e.queries = append(e.queries, body.Query)
+	e.mu.Unlock()
+	result := "[]"
+	if strings.HasPrefix(body.Query, "mutation") {
+		result = `{"id": "x"}`
+	}
+	if e.respond != nil {
+		result = e.respond(body.Query)
+	}
+	fmt.Fprintf(w, `{"data": {"result": %s}}`, result)
+}
+
+// last returns the last query sent
+func (e *engine) last() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.queries[len(e.queries)-1]
+}
+
+// count returns how many queries contain s
+func (e *engine) count(s string) (n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, q := range e.queries {
+		n += strings.Count(q, s)
+	}
+	return n
+}
+
+// distinct returns the distinct queries containing s
+func (e *engine) distinct(s string) map[string]bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	queries := map[string]bool{}
+	for _, q := range e.queries {
+		if strings.Contains(q, s) {
+			queries[q] = true
+		}
+	}
+	return queries
+}
+
+func TestSharedBuilders(t *testing.T) {
+	e := &engine{}
+	server := httptest.NewServer(e)
+	defer server.Close()
+	client := prisma.New(server.URL)
+
+	where := post.Where().Published(true)
+	order := user.Order().Name(prisma.ASC)
+	input := user.New().Email("ada@prisma.io")
+
+	// the queries of the bases before anything is derived from them
+	if _, err := client.Post.FindMany(where); err != nil {
+		t.Fatal(err)
+	}
+	whereQuery := e.last()
+	if _, err := client.User.FindMany(order); err != nil {
+		t.Fatal(err)
+	}
+	orderQuery := e.last()
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			title := fmt.Sprintf("title-%d", i)
+			if _, err := client.Post.FindMany(where.TitleContains(title)); err != nil {
+				t.Error(err)
+			}
+			if _, err := client.User.FindMany(order.Balance(prisma.DESC), user.Where().NameContains(title)); err != nil {
+				t.Error(err)
+			}
+			if _, err := client.User.Create(input.Name(title)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// every derived builder compiles to its own query, and none of them is
+	// the query of its base
+	for _, kind := range []struct{ name, base string }{
+		{"findManyPost", whereQuery},
+		{"findManyUser", orderQuery},
+		{"createOneUser", ""},
+	} {
+		queries := e.distinct(kind.name)
+		delete(queries, kind.base)
+		if len(queries) != n {
+			t.Fatalf("expected %d distinct %s queries besides the base, got %d", n, kind.name, len(queries))
+		}
+	}
+
+	// and every derived condition and input only has its own fields
+	for i := 0; i < n; i++ {
+		title := fmt.Sprintf("%q", fmt.Sprintf("title-%d", i))
+		if got := e.count("title_contains: " + title); got != 1 {
+			t.Fatalf("expected one query with title %s, got %d", title, got)
+		}
+		if got := e.count("name: " + title); got != 1 {
+			t.Fatalf("expected one user named %s, got %d", title, got)
+		}
+	}
+	if got := e.count("title_contains"); got != n {
+		t.Fatalf("expected %d title filters, got %d", n, got)
+	}
+	if got := e.count("balance: DESC"); got != n {
+		t.Fatalf("expected %d balance orderings, got %d", n, got)
+	}
+
+	// and the bases are unchanged
+	if _, err := client.Post.FindMany(where); err != nil {
+		t.Fatal(err)
+	}
+	if e.last() != whereQuery {
+		t.Fatalf("the shared condition changed:\n%s\n%s", whereQuery, e.last())
+	}
+	if _, err := client.User.FindMany(order); err != nil {
+		t.Fatal(err)
+	}
+	if e.last() != orderQuery {
+		t.Fatalf("the shared ordering changed:\n%s\n%s", orderQuery, e.last())
+	}
+	if _, err := client.User.Create(input); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(e.last(), "name:") {
+		t.Fatalf("the shared input changed: %s", e.last())
+	}
+}
@@ -0,0 +1,48 @@
+package prisma
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// cache of lazily loaded relations. It lives on the client returned by
+// WithContext, so it only lasts as long as the request.
+type cache struct {
+	mu      sync.Mutex
+	results map[string]json.RawMessage
+}
+
+func newCache() *cache {
+	return &cache{results: map[string]json.RawMessage{}}
+}
+
+// get a cached result by query
+func (c *cache) get(query string) (json.RawMessage, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.results[query]
+	return result, ok
+}
+
+// set a result for the query
+func (c *cache) set(query string, result json.RawMessage) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.results[query] = result
+	c.mu.Unlock()
+}
+
+// reset the cache after a write, since the cached results may be stale
+func (c *cache) reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.results = map[string]json.RawMessage{}
+	c.mu.Unlock()
+}
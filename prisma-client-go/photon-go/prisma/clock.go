@@ -0,0 +1,87 @@
+package prisma
+
+import "time"
+
+// Clock tells the time of the timestamps the client fills in
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is a function used as a Clock, like a fixed time in tests
+type ClockFunc func() time.Time
+
+// Now implements Clock
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// WithClock returns a copy of the client that fills in timestamps with the
+// time told by clock instead of the system's time
+func (c *Client) WithClock(clock Clock) *Client {
+	client := *c
+	client.clock = clock
+	return client.bind()
+}
+
+// now is the time of the timestamps filled in by the client
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// stamp fills in the fields of data and of its nested creates that are
+// generated by the client. IDs and fields marked @createdAt are set on
+// create and fields marked @updatedAt on every write, unless they're
+// already set.
+func (r *rules) stamp(data object, create bool, now time.Time) object {
+	for _, rule := range r.fields {
+		if rule.relation != "" {
+			if nested, ok := data.get(rule.field); ok {
+				data = data.set(rule.field, modelRules[rule.relation].stampNested(nested, now))
+			}
+			continue
+		}
+		if _, ok := data.get(rule.field); ok {
+			continue
+		}
+		switch {
+		case rule.generate != nil && create:
+			data = data.set(rule.field, rule.generate())
+		case rule.updatedAt, rule.createdAt && create:
+			data = data.set(rule.field, now)
+		}
+	}
+	return data
+}
+
+// stampNested fills in the generated fields of the records created by a
+// nested write
+func (r *rules) stampNested(nested interface{}, now time.Time) interface{} {
+	o, ok := nested.(object)
+	if !ok {
+		return nested
+	}
+	switch creates, _ := o.get("create"); v := creates.(type) {
+	case object:
+		o = o.set("create", r.stamp(v, true, now))
+	case []object:
+		out := make([]object, len(v))
+		for i, create := range v {
+			out[i] = r.stamp(create, true, now)
+		}
+		o = o.set("create", out)
+	}
+	return o
+}
+
+// stampRows fills in the generated fields of the rows of a bulk create,
+// every row gets the same time
+func (r *rules) stampRows(rows []object, now time.Time) []object {
+	out := make([]object, len(rows))
+	for i, row := range rows {
+		out[i] = r.stamp(row, true, now)
+	}
+	return out
+}
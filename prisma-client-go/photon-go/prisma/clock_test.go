@@ -0,0 +1,100 @@
+package prisma
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixed is the time told by the clock of the tests
+var fixed = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// clockClient returns a client that's always at the fixed time
+func clockClient() (*Client, *testDB) {
+	client, db := testClient(func(query string) string {
+		if strings.Contains(query, "findMany") {
+			return `[{"id": "x"}]`
+		}
+		if strings.Contains(query, "Many") {
+			return `{"count": 1}`
+		}
+		return `{"id": "x"}`
+	})
+	return client.WithClock(ClockFunc(func() time.Time { return fixed })), db
+}
+
+func TestClockCreate(t *testing.T) {
+	client, db := clockClient()
+	if _, err := client.Post.Create((&PostInput{}).Title("a")); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	for _, want := range []string{`createdAt: "2020-01-02T03:04:05Z"`, `updatedAt: "2020-01-02T03:04:05Z"`} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("expected %s in %s", want, query)
+		}
+	}
+}
+
+func TestClockUpdate(t *testing.T) {
+	client, db := clockClient()
+	if _, err := client.Post.Update((&PostInput{}).Title("b"), (&PostWhere{}).ID("p1")); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	if !strings.Contains(query, `updatedAt: "2020-01-02T03:04:05Z"`) {
+		t.Fatalf("expected updatedAt to be set in %s", query)
+	}
+	if strings.Contains(query, `createdAt: "`) {
+		t.Fatalf("expected createdAt not to change in %s", query)
+	}
+	// comments have no updatedAt, so their updates aren't stamped
+	if _, err := client.Comment.Update((&CommentInput{}).Text("b"), (&CommentWhere{}).ID("c1")); err != nil {
+		t.Fatal(err)
+	}
+	if query := db.sent()[len(db.sent())-1]; strings.Contains(query, "2020") {
+		t.Fatalf("expected no timestamps in %s", query)
+	}
+}
+
+func TestClockNestedCreate(t *testing.T) {
+	client, db := clockClient()
+	user := (&UserInput{}).Email("ada@prisma.io").CreatePosts((&PostInput{}).Title("a"), (&PostInput{}).Title("b"))
+	if _, err := client.User.Create(user); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	// users have no timestamps, only the posts created with them are stamped
+	if n := strings.Count(query, `createdAt: "2020-01-02T03:04:05Z"`); n != 2 {
+		t.Fatalf("expected both posts to be stamped, got %d in %s", n, query)
+	}
+	if n := strings.Count(query, `updatedAt: "2020-01-02T03:04:05Z"`); n != 2 {
+		t.Fatalf("expected both posts to be stamped, got %d in %s", n, query)
+	}
+}
+
+func TestClockCreateMany(t *testing.T) {
+	client, db := clockClient()
+	posts := []*PostInput{(&PostInput{}).Title("a"), (&PostInput{}).Title("b"), (&PostInput{}).Title("c")}
+	if _, err := client.Post.CreateMany(context.Background(), posts, nil); err != nil {
+		t.Fatal(err)
+	}
+	var query string
+	for _, q := range db.sent() {
+		if strings.Contains(q, "createManyPost") {
+			query = q
+		}
+	}
+	if n := strings.Count(query, `createdAt: "2020-01-02T03:04:05Z"`); n != 3 {
+		t.Fatalf("expected every row to be stamped, got %d in %s", n, query)
+	}
+}
+
+func TestClockSystem(t *testing.T) {
+	client, _ := testClient(nil)
+	before := time.Now()
+	if now := client.now(); now.Before(before) || now.After(time.Now()) {
+		t.Fatalf("expected the system's time without a clock, got %v", now)
+	}
+}
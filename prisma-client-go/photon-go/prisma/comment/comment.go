@@ -27,16 +27,88 @@ func New() *prisma.CommentInput {
 	return &prisma.CommentInput{}
 }
 
+// Field enum
+var Field = struct {
+	ID        prisma.CommentField
+	CreatedAt prisma.CommentField
+	Text      prisma.CommentField
+}{
+	ID:        "id",
+	CreatedAt: "createdAt",
+	Text:      "text",
+}
+
+// Having condition on grouped comments
+func Having() *prisma.CommentHaving {
+	return &prisma.CommentHaving{}
+}
+
+// GroupOrder condition on grouped comments
+func GroupOrder() *prisma.CommentGroupOrder {
+	return &prisma.CommentGroupOrder{}
+}
+
+// Distinct condition, returns one comment for each distinct set of fields
+func Distinct(fields ...prisma.CommentField) *prisma.CommentDistinct {
+	c := prisma.CommentDistinct(fields)
+	return &c
+}
+
 // Where condition
 func Where() *prisma.CommentWhere {
 	return &prisma.CommentWhere{}
 }
 
+// If requires the conditions to still hold when updating the comment
+func If(conditions ...*prisma.CommentWhere) *prisma.CommentWhere {
+	return (&prisma.CommentWhere{}).If(conditions...)
+}
+
 // Order condition
 func Order() *prisma.CommentOrder {
 	return &prisma.CommentOrder{}
 }
 
+// First condition
+func First(first int) *prisma.CommentFirst {
+	c := prisma.CommentFirst(first)
+	return &c
+}
+
+// After condition
+func After(after string) *prisma.CommentAfter {
+	c := prisma.CommentAfter(after)
+	return &c
+}
+
+// Before condition
+func Before(before string) *prisma.CommentBefore {
+	c := prisma.CommentBefore(before)
+	return &c
+}
+
+// Skip condition
+func Skip(skip int) *prisma.CommentSkip {
+	c := prisma.CommentSkip(skip)
+	return &c
+}
+
+// Last condition
+func Last(last int) *prisma.CommentLast {
+	c := prisma.CommentLast(last)
+	return &c
+}
+
+// WithPost includes the post the comment belongs to
+func WithPost(with ...*prisma.PostWith) *prisma.CommentWith {
+	return (&prisma.CommentWith{}).Post(with...)
+}
+
+// WithWrittenBy includes the author of the comment
+func WithWrittenBy(with ...*prisma.UserWith) *prisma.CommentWith {
+	return (&prisma.CommentWith{}).WrittenBy(with...)
+}
+
 // Find a comment by a condition
 func Find(db prisma.Client, conditions ...prisma.CommentCondition) (comment *Comment, err error) {
 	return comment, err
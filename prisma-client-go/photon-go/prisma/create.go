@@ -0,0 +1,154 @@
+package prisma
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultChunkSize is how many records CreateMany sends per operation
+const DefaultChunkSize = 1000
+
+// codeUniqueConstraint is the engine's error code for unique violations
+const codeUniqueConstraint = "P2002"
+
+// CreateManyOptions configures CreateMany
+type CreateManyOptions struct {
+	// SkipDuplicates skips the rows that would violate a unique constraint
+	// instead of failing
+	SkipDuplicates bool
+	// ChunkSize is how many rows are sent per operation, DefaultChunkSize
+	// when zero
+	ChunkSize int
+}
+
+func (o *CreateManyOptions) chunkSize() int {
+	if o == nil || o.ChunkSize <= 0 {
+		return DefaultChunkSize
+	}
+	return o.ChunkSize
+}
+
+func (o *CreateManyOptions) skipDuplicates() bool {
+	return o != nil && o.SkipDuplicates
+}
+
+// RowError is a row passed to CreateMany that violates a unique constraint
+type RowError struct {
+	Index int    // Index of the row within the inputs
+	Field string // Field that isn't unique
+}
+
+// Error implements error
+func (e *RowError) Error() string {
+	return fmt.Sprintf("prisma: row %d: unique constraint failed on %s", e.Index, e.Field)
+}
+
+// CreateManyError lists the rows of CreateMany that violate a unique
+// constraint
+type CreateManyError struct {
+	Rows []*RowError
+}
+
+// Error implements error
+func (e *CreateManyError) Error() string {
+	if len(e.Rows) == 1 {
+		return e.Rows[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more rows)", e.Rows[0].Error(), len(e.Rows)-1)
+}
+
+// createMany sends the rows in chunks and adds up how many were created.
+// Rows that repeat a unique field of an earlier row fail before anything is
+// sent. Chunks are separate operations, so when a chunk fails the rows of
+// the chunks before it stay created and are included in the count.
+func (c *Client) createMany(ctx context.Context, model string, unique []string, rows []object, opts *CreateManyOptions) (*BatchPayload, error) {
+	if !opts.skipDuplicates() {
+		if errs := duplicateRows(unique, rows); len(errs) > 0 {
+			return nil, &CreateManyError{errs}
+		}
+	}
+	var total BatchPayload
+	size := opts.chunkSize()
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		args := object{{"data", rows[start:end]}}
+		if opts.skipDuplicates() {
+			args = args.set("skipDuplicates", true)
+		}
+		op := &operation{
+			mutation:  true,
+			name:      "createMany" + model,
+			args:      args,
+			selection: "count",
+		}
+		var result BatchPayload
+		if err := c.sendContext(ctx, op, &result); err != nil {
+			if e, ok := err.(*EngineError); !ok || e.Code != codeUniqueConstraint {
+				return &total, err
+			}
+			errs, lookup := c.conflictingRows(ctx, model, unique, rows[start:end], start)
+			if lookup != nil || len(errs) == 0 {
+				return &total, err
+			}
+			return &total, &CreateManyError{errs}
+		}
+		total.Count += result.Count
+	}
+	return &total, nil
+}
+
+// duplicateRows finds the rows that repeat a unique field of an earlier row
+func duplicateRows(unique []string, rows []object) (errs []*RowError) {
+	for _, name := range unique {
+		seen := map[interface{}]bool{}
+		for i, row := range rows {
+			value, ok := row.get(name)
+			if !ok || value == nil {
+				continue
+			}
+			if seen[value] {
+				errs = append(errs, &RowError{Index: i, Field: name})
+			}
+			seen[value] = true
+		}
+	}
+	return errs
+}
+
+// conflictingRows looks up which rows have a unique field that's already
+// taken by an existing record
+func (c *Client) conflictingRows(ctx context.Context, model string, unique []string, rows []object, offset int) (errs []*RowError, err error) {
+	for _, name := range unique {
+		var values []interface{}
+		for _, row := range rows {
+			if value, ok := row.get(name); ok && value != nil {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		op := &operation{
+			name:      "findMany" + model,
+			args:      object{{"where", object{{name + "_in", values}}}},
+			selection: name,
+		}
+		var records []map[string]interface{}
+		if err := c.sendContext(ctx, op, &records); err != nil {
+			return nil, err
+		}
+		taken := map[interface{}]bool{}
+		for _, record := range records {
+			taken[record[name]] = true
+		}
+		for i, row := range rows {
+			if value, _ := row.get(name); value != nil && taken[value] {
+				errs = append(errs, &RowError{Index: offset + i, Field: name})
+			}
+		}
+	}
+	return errs, nil
+}
@@ -0,0 +1,83 @@
+package prisma
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/prisma/specs/photongo/photon-go/prisma/id"
+)
+
+func TestCreateManyDuplicateID(t *testing.T) {
+	client, db := testClient(nil)
+	_, err := client.User.CreateMany(context.Background(), []*UserInput{
+		(&UserInput{}).ID("u1").Email("ada@prisma.io"),
+		(&UserInput{}).ID("u2").Email("grace@prisma.io"),
+		(&UserInput{}).ID("u1").Email("alan@prisma.io"),
+	}, nil)
+	e, ok := err.(*CreateManyError)
+	if !ok {
+		t.Fatalf("expected a CreateManyError, got %v", err)
+	}
+	if len(e.Rows) != 1 || e.Rows[0].Index != 2 || e.Rows[0].Field != "id" {
+		t.Fatalf("expected row 2 to repeat the id, got %v", e)
+	}
+	if len(db.sent()) != 0 {
+		t.Fatalf("expected nothing to be sent, got %v", db.sent())
+	}
+}
+
+func TestCreateManyTakenID(t *testing.T) {
+	client, db := testClient(func(query string) string {
+		if strings.Contains(query, "findManyUser(where: {id_in") {
+			return `[{"id": "u2"}]`
+		}
+		return `[]`
+	})
+	db.fail = func(query string) error {
+		if strings.Contains(query, "createManyUser") {
+			return &EngineError{Code: codeUniqueConstraint, Message: "Unique constraint failed"}
+		}
+		return nil
+	}
+	_, err := client.User.CreateMany(context.Background(), []*UserInput{
+		(&UserInput{}).ID("u1").Email("ada@prisma.io"),
+		(&UserInput{}).ID("u2").Email("grace@prisma.io"),
+	}, nil)
+	e, ok := err.(*CreateManyError)
+	if !ok {
+		t.Fatalf("expected a CreateManyError, got %v", err)
+	}
+	if len(e.Rows) != 1 || e.Rows[0].Index != 1 || e.Rows[0].Field != "id" {
+		t.Fatalf("expected row 1 to have a taken id, got %v", e)
+	}
+}
+
+// generatedID matches the id generated for a create, which comes after the
+// fields of the input
+var generatedID = regexp.MustCompile(`["}], id: "([^"]+)"`)
+
+func TestGeneratedIDs(t *testing.T) {
+	client, db := testClient(func(string) string { return `{"id": "x"}` })
+	if _, err := client.User.Create((&UserInput{}).Email("ada@prisma.io")); err != nil {
+		t.Fatal(err)
+	}
+	comment := (&CommentInput{}).Text("hi").
+		ConnectPost((&PostConnect{}).ID("p1")).
+		ConnectWrittenBy((&UserConnect{}).ID("u1"))
+	if _, err := client.Comment.Create(comment); err != nil {
+		t.Fatal(err)
+	}
+	sent := db.sent()
+	user := generatedID.FindStringSubmatch(sent[0])
+	if user == nil || !id.IsCUID(user[1]) {
+		t.Fatalf("expected a CUID in %s", sent[0])
+	}
+	// comment ids are @default(uuid())
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	c := generatedID.FindStringSubmatch(sent[1])
+	if c == nil || !uuid.MatchString(c[1]) {
+		t.Fatalf("expected a UUIDv4 in %s", sent[1])
+	}
+}
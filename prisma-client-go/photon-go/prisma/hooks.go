@@ -0,0 +1,250 @@
+package prisma
+
+import "sync"
+
+// hookEvent is a write that hooks can be registered for
+type hookEvent int
+
+const (
+	hookCreate hookEvent = iota
+	hookUpdate
+	hookDelete
+)
+
+// hookKey identifies the hooks of one event of a model
+type hookKey struct {
+	model string
+	event hookEvent
+	after bool
+}
+
+// hookFunc is a hook with its argument type erased, the typed functions are
+// wrapped when they're registered
+type hookFunc func(c *Client, v interface{}) error
+
+// hooks are the lifecycle hooks of a client. They're shared by the clients
+// returned by WithContext and Transaction, so hooks registered on a client
+// also run for writes made through its copies.
+type hooks struct {
+	mu  sync.RWMutex
+	fns map[hookKey][]hookFunc
+}
+
+func newHooks() *hooks {
+	return &hooks{fns: map[hookKey][]hookFunc{}}
+}
+
+// add a hook, hooks run in the order they were added
+func (h *hooks) add(key hookKey, fn hookFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fns[key] = append(h.fns[key], fn)
+}
+
+// get the hooks registered for key
+func (h *hooks) get(key hookKey) []hookFunc {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.fns[key]
+}
+
+// hooked runs write between the before and after hooks of the event. The
+// before hooks receive input and the after hooks the result of write, and
+// any of them can fail the write by returning an error. Inputs are
+// immutable, so before hooks can veto a write but not change it. Without
+// hooks write runs on its own. With hooks and a transport that supports
+// transactions, they run within the same transaction as the write, so a
+// failing after hook also rolls back the write and whatever the other hooks
+// wrote.
+func (c *Client) hooked(model string, event hookEvent, input interface{}, write func(c *Client) (interface{}, error)) (interface{}, error) {
+	before := c.hooks.get(hookKey{model, event, false})
+	after := c.hooks.get(hookKey{model, event, true})
+	if len(before) == 0 && len(after) == 0 {
+		return write(c)
+	}
+	var result interface{}
+	run := func(c *Client) (err error) {
+		for _, fn := range before {
+			if err := fn(c, input); err != nil {
+				return err
+			}
+		}
+		if result, err = write(c); err != nil {
+			return err
+		}
+		for _, fn := range after {
+			if err := fn(c, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := c.atomically(run); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BeforeCreate registers a hook that's called with the input of every user
+// created through Create. Returning an error cancels the create, the input
+// can't be changed. CreateMany and Upsert bypass it.
+func (u *UserModel) BeforeCreate(fn func(c *Client, user *UserInput) error) {
+	u.client.hooks.add(hookKey{"User", hookCreate, false}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*UserInput))
+	})
+}
+
+// AfterCreate registers a hook that's called with every user created
+// through Create. Returning an error fails the create, which is rolled back
+// when the transport supports transactions. It's bypassed by CreateMany
+// and Upsert.
+func (u *UserModel) AfterCreate(fn func(c *Client, user *User) error) {
+	u.client.hooks.add(hookKey{"User", hookCreate, true}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*User))
+	})
+}
+
+// BeforeUpdate registers a hook that's called with the input of every user
+// updated through Update. Returning an error cancels the update, the input
+// can't be changed. UpdateMany and Upsert bypass it.
+func (u *UserModel) BeforeUpdate(fn func(c *Client, user *UserInput) error) {
+	u.client.hooks.add(hookKey{"User", hookUpdate, false}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*UserInput))
+	})
+}
+
+// AfterUpdate registers a hook that's called with every user updated
+// through Update. Returning an error fails the update, which is rolled back
+// when the transport supports transactions. It's bypassed by UpdateMany
+// and Upsert.
+func (u *UserModel) AfterUpdate(fn func(c *Client, user *User) error) {
+	u.client.hooks.add(hookKey{"User", hookUpdate, true}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*User))
+	})
+}
+
+// BeforeDelete registers a hook that's called with the condition of every
+// user deleted through Delete. Returning an error cancels the delete.
+// DeleteMany bypasses it.
+func (u *UserModel) BeforeDelete(fn func(c *Client, where *UserWhere) error) {
+	u.client.hooks.add(hookKey{"User", hookDelete, false}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*UserWhere))
+	})
+}
+
+// AfterDelete registers a hook that's called with every user deleted
+// through Delete. Returning an error fails the delete, which is rolled back
+// when the transport supports transactions. DeleteMany bypasses it.
+func (u *UserModel) AfterDelete(fn func(c *Client, user *User) error) {
+	u.client.hooks.add(hookKey{"User", hookDelete, true}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*User))
+	})
+}
+
+// BeforeCreate registers a hook that's called with the input of every post
+// created through Create. Returning an error cancels the create, the input
+// can't be changed. CreateMany bypasses it.
+func (p *PostModel) BeforeCreate(fn func(c *Client, post *PostInput) error) {
+	p.client.hooks.add(hookKey{"Post", hookCreate, false}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*PostInput))
+	})
+}
+
+// AfterCreate registers a hook that's called with every post created
+// through Create. Returning an error fails the create, which is rolled back
+// when the transport supports transactions. CreateMany bypasses it.
+func (p *PostModel) AfterCreate(fn func(c *Client, post *Post) error) {
+	p.client.hooks.add(hookKey{"Post", hookCreate, true}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*Post))
+	})
+}
+
+// BeforeUpdate registers a hook that's called with the input of every post
+// updated through Update. Returning an error cancels the update, the input
+// can't be changed. UpdateMany bypasses it.
+func (p *PostModel) BeforeUpdate(fn func(c *Client, post *PostInput) error) {
+	p.client.hooks.add(hookKey{"Post", hookUpdate, false}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*PostInput))
+	})
+}
+
+// AfterUpdate registers a hook that's called with every post updated
+// through Update. Returning an error fails the update, which is rolled back
+// when the transport supports transactions. UpdateMany bypasses it.
+func (p *PostModel) AfterUpdate(fn func(c *Client, post *Post) error) {
+	p.client.hooks.add(hookKey{"Post", hookUpdate, true}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*Post))
+	})
+}
+
+// BeforeDelete registers a hook that's called with the condition of every
+// post deleted through Delete. Returning an error cancels the delete.
+// DeleteMany bypasses it.
+func (p *PostModel) BeforeDelete(fn func(c *Client, where *PostWhere) error) {
+	p.client.hooks.add(hookKey{"Post", hookDelete, false}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*PostWhere))
+	})
+}
+
+// AfterDelete registers a hook that's called with every post deleted
+// through Delete. Returning an error fails the delete, which is rolled back
+// when the transport supports transactions. DeleteMany bypasses it.
+func (p *PostModel) AfterDelete(fn func(c *Client, post *Post) error) {
+	p.client.hooks.add(hookKey{"Post", hookDelete, true}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*Post))
+	})
+}
+
+// BeforeCreate registers a hook that's called with the input of every
+// comment created through Create. Returning an error cancels the create,
+// the input can't be changed. CreateMany bypasses it.
+func (c *CommentModel) BeforeCreate(fn func(c *Client, comment *CommentInput) error) {
+	c.client.hooks.add(hookKey{"Comment", hookCreate, false}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*CommentInput))
+	})
+}
+
+// AfterCreate registers a hook that's called with every comment created
+// through Create. Returning an error fails the create, which is rolled back
+// when the transport supports transactions. CreateMany bypasses it.
+func (c *CommentModel) AfterCreate(fn func(c *Client, comment *Comment) error) {
+	c.client.hooks.add(hookKey{"Comment", hookCreate, true}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*Comment))
+	})
+}
+
+// BeforeUpdate registers a hook that's called with the input of every
+// comment updated through Update. Returning an error cancels the update,
+// the input can't be changed. UpdateMany bypasses it.
+func (c *CommentModel) BeforeUpdate(fn func(c *Client, comment *CommentInput) error) {
+	c.client.hooks.add(hookKey{"Comment", hookUpdate, false}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*CommentInput))
+	})
+}
+
+// AfterUpdate registers a hook that's called with every comment updated
+// through Update. Returning an error fails the update, which is rolled back
+// when the transport supports transactions. UpdateMany bypasses it.
+func (c *CommentModel) AfterUpdate(fn func(c *Client, comment *Comment) error) {
+	c.client.hooks.add(hookKey{"Comment", hookUpdate, true}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*Comment))
+	})
+}
+
+// BeforeDelete registers a hook that's called with the condition of every
+// comment deleted through Delete. Returning an error cancels the delete.
+// DeleteMany bypasses it.
+func (c *CommentModel) BeforeDelete(fn func(c *Client, where *CommentWhere) error) {
+	c.client.hooks.add(hookKey{"Comment", hookDelete, false}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*CommentWhere))
+	})
+}
+
+// AfterDelete registers a hook that's called with every comment deleted
+// through Delete. Returning an error fails the delete, which is rolled back
+// when the transport supports transactions. DeleteMany bypasses it.
+func (c *CommentModel) AfterDelete(fn func(c *Client, comment *Comment) error) {
+	c.client.hooks.add(hookKey{"Comment", hookDelete, true}, func(c *Client, v interface{}) error {
+		return fn(c, v.(*Comment))
+	})
+}
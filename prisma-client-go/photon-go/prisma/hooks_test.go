@@ -0,0 +1,131 @@
+package prisma
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// hookClient returns a client on a transactor answering every write with
+// a user
+func hookClient() (*Client, *testDB) {
+	db := &testDB{respond: func(query string) string {
+		if strings.Contains(query, "findMany") {
+			return `[]`
+		}
+		return `{"id": "u1", "name": "Ada"}`
+	}}
+	return newClient(&testTransactor{testDB: db}), db
+}
+
+// kinds returns the first word of each query, which tells the transaction
+// statements and the operations apart
+func kinds(queries []string) string {
+	var words []string
+	for _, query := range queries {
+		query = strings.TrimPrefix(query, "tx ")
+		if i := strings.Index(query, "result: "); i >= 0 {
+			query = query[i+len("result: "):]
+		}
+		if i := strings.IndexAny(query, "( "); i >= 0 {
+			query = query[:i]
+		}
+		words = append(words, query)
+	}
+	return strings.Join(words, " ")
+}
+
+func TestHooksWithoutHooks(t *testing.T) {
+	client, db := hookClient()
+	if _, err := client.User.Create((&UserInput{}).Name("Ada").Email("ada@prisma.io")); err != nil {
+		t.Fatal(err)
+	}
+	// writes without hooks don't need a transaction
+	if got := kinds(db.sent()); got != "createOneUser" {
+		t.Fatalf("expected a single create, got %v", db.sent())
+	}
+}
+
+func TestHooksOrder(t *testing.T) {
+	client, db := hookClient()
+	var calls []string
+	hook := func(name string) func(*Client, *UserInput) error {
+		return func(tx *Client, _ *UserInput) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	client.User.BeforeUpdate(hook("before 1"))
+	client.User.BeforeUpdate(hook("before 2"))
+	client.User.AfterUpdate(func(tx *Client, u *User) error {
+		calls = append(calls, "after "+u.Name.String)
+		// hooks get the transaction's client
+		_, err := tx.Post.FindMany()
+		return err
+	})
+	if _, err := client.User.Update((&UserInput{}).Name("Ada"), (&UserWhere{}).ID("u1")); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(calls, ", "); got != "before 1, before 2, after Ada" {
+		t.Fatalf("expected the hooks in the order they were added, got %s", got)
+	}
+	if got := kinds(db.sent()); got != "BEGIN updateOneUser findManyPost COMMIT" {
+		t.Fatalf("expected the write and the hooks in a transaction, got %v", db.sent())
+	}
+	for _, query := range db.sent()[1:3] {
+		if !strings.HasPrefix(query, "tx ") {
+			t.Fatalf("expected %s to be sent within the transaction", query)
+		}
+	}
+}
+
+func TestHooksVeto(t *testing.T) {
+	client, db := hookClient()
+	vetoed := errors.New("vetoed")
+	client.User.BeforeCreate(func(tx *Client, u *UserInput) error {
+		return vetoed
+	})
+	after := false
+	client.User.AfterCreate(func(tx *Client, u *User) error {
+		after = true
+		return nil
+	})
+	if _, err := client.User.Create((&UserInput{}).Name("Ada").Email("ada@prisma.io")); err != vetoed {
+		t.Fatalf("expected the veto, got %v", err)
+	}
+	if after {
+		t.Fatal("expected the after hook not to run")
+	}
+	if got := kinds(db.sent()); got != "BEGIN ROLLBACK" {
+		t.Fatalf("expected nothing to be written, got %v", db.sent())
+	}
+}
+
+func TestHooksImmutableInput(t *testing.T) {
+	client, db := hookClient()
+	client.User.BeforeCreate(func(tx *Client, u *UserInput) error {
+		// setters return a copy, so the write is unchanged
+		u.Email("eve@prisma.io")
+		return nil
+	})
+	if _, err := client.User.Create((&UserInput{}).Name("Ada").Email("ada@prisma.io")); err != nil {
+		t.Fatal(err)
+	}
+	if query := db.sent()[1]; !strings.Contains(query, "ada@prisma.io") || strings.Contains(query, "eve@prisma.io") {
+		t.Fatalf("expected the hook not to change the input, got %s", query)
+	}
+}
+
+func TestHooksAfterError(t *testing.T) {
+	client, db := hookClient()
+	failed := errors.New("failed")
+	client.User.AfterDelete(func(tx *Client, u *User) error {
+		return failed
+	})
+	if _, err := client.User.Delete((&UserWhere{}).ID("u1")); err != failed {
+		t.Fatalf("expected the hook's error, got %v", err)
+	}
+	if got := kinds(db.sent()); got != "BEGIN deleteOneUser ROLLBACK" {
+		t.Fatalf("expected the delete to be rolled back, got %v", db.sent())
+	}
+}
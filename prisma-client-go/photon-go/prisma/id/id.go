@@ -0,0 +1,175 @@
+// Package id generates the IDs of fields marked @default(cuid()) and
+// @default(uuid()). Every generator is safe for concurrent use.
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalid is returned when parsing something that isn't an ID
+var ErrInvalid = errors.New("id: invalid id")
+
+const (
+	base = 36
+	// blockSize is the length of the counter, fingerprint and random blocks
+	// of a CUID
+	blockSize = 4
+	// blockMax is the number of values of a block
+	blockMax = base * base * base * base
+	// cuidLength is the length of a CUID generated before the year 2059,
+	// when the timestamp grows to 9 characters
+	cuidLength = 25
+)
+
+var (
+	counter     uint32
+	fingerprint = hostFingerprint()
+)
+
+// CUID returns a collision-resistant id, like cjsx2j8bw02920b25rl806l07. It
+// starts with a c followed by the time in milliseconds, so CUIDs sort by the
+// time they were created, then a counter, a fingerprint of the host and
+// random characters.
+func CUID() string {
+	var b strings.Builder
+	b.Grow(cuidLength)
+	b.WriteString("c")
+	b.WriteString(strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), base))
+	b.WriteString(pad(strconv.FormatUint(uint64(atomic.AddUint32(&counter, 1)%blockMax), base), blockSize))
+	b.WriteString(fingerprint)
+	b.WriteString(randomBlock())
+	b.WriteString(randomBlock())
+	return b.String()
+}
+
+// IsCUID reports whether s looks like a CUID
+func IsCUID(s string) bool {
+	_, err := CUIDTime(s)
+	return err == nil
+}
+
+// CUIDTime returns the time a CUID was created at, to the millisecond
+func CUIDTime(cuid string) (time.Time, error) {
+	if len(cuid) < cuidLength || cuid[0] != 'c' {
+		return time.Time{}, ErrInvalid
+	}
+	// the time takes up everything but the c and the 4 blocks after it
+	ms, err := strconv.ParseInt(cuid[1:len(cuid)-4*blockSize], base, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalid
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
+
+// hostFingerprint identifies the process, so CUIDs created in the same
+// millisecond by different hosts are different
+func hostFingerprint() string {
+	host, _ := os.Hostname()
+	sum := len(host) + base
+	for _, c := range host {
+		sum += int(c)
+	}
+	return pad(strconv.FormatInt(int64(os.Getpid()), base), 2) + pad(strconv.FormatInt(int64(sum), base), 2)
+}
+
+// randomBlock returns a block of random base36 characters
+func randomBlock() string {
+	var b [4]byte
+	random(b[:])
+	return pad(strconv.FormatUint(uint64(binary.BigEndian.Uint32(b[:])%blockMax), base), blockSize)
+}
+
+// pad s with leading zeros to n characters, or keep its last n characters
+// when it's longer
+func pad(s string, n int) string {
+	if len(s) >= n {
+		return s[len(s)-n:]
+	}
+	return strings.Repeat("0", n-len(s)) + s
+}
+
+// random fills b with random bytes
+func random(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("id: unable to read random bytes: %v", err))
+	}
+}
+
+// UUIDv4 returns a random UUID, which is what @default(uuid()) fields get
+func UUIDv4() string {
+	var b [16]byte
+	random(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return format(b)
+}
+
+// v7 keeps the UUIDv7s of this process in order when several are created
+// in the same millisecond
+var v7 struct {
+	sync.Mutex
+	ms  int64
+	seq uint16
+}
+
+// UUIDv7 returns a UUID that starts with the time in milliseconds, so it
+// sorts by the time it was created. UUIDs created by this process within
+// the same millisecond are numbered in order.
+func UUIDv7() string {
+	var b [16]byte
+	random(b[:])
+	ms := time.Now().UnixNano() / int64(time.Millisecond)
+	v7.Lock()
+	if ms > v7.ms {
+		// start at a random number with room left to count up
+		v7.ms, v7.seq = ms, binary.BigEndian.Uint16(b[6:8])&0x7ff
+	} else if v7.seq++; v7.seq > 0xfff {
+		v7.ms, v7.seq = v7.ms+1, 0
+	}
+	ms, seq := v7.ms, v7.seq
+	v7.Unlock()
+	var t [8]byte
+	binary.BigEndian.PutUint64(t[:], uint64(ms))
+	copy(b[:6], t[2:])
+	b[6] = 0x70 | byte(seq>>8)
+	b[7] = byte(seq)
+	b[8] = b[8]&0x3f | 0x80
+	return format(b)
+}
+
+// UUIDTime returns the time a UUIDv7 was created at, to the millisecond
+func UUIDTime(uuid string) (time.Time, error) {
+	b, err := hex.DecodeString(strings.Replace(uuid, "-", "", -1))
+	if err != nil || len(b) != 16 || b[6]>>4 != 7 {
+		return time.Time{}, ErrInvalid
+	}
+	var t [8]byte
+	copy(t[2:], b[:6])
+	ms := int64(binary.BigEndian.Uint64(t[:]))
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
+
+// format a UUID as 8-4-4-4-12 hex digits
+func format(b [16]byte) string {
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
@@ -0,0 +1,151 @@
+package id
+
+import (
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var uuidFormat = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// bytes decodes a UUID, failing the test when it isn't formatted like one
+func bytes(t *testing.T, uuid string) []byte {
+	t.Helper()
+	if !uuidFormat.MatchString(uuid) {
+		t.Fatalf("expected a formatted UUID, got %s", uuid)
+	}
+	b, _ := hex.DecodeString(strings.Replace(uuid, "-", "", -1))
+	return b
+}
+
+// between checks that created, which is to the millisecond, is within the
+// milliseconds of before and after
+func between(t *testing.T, created, before, after time.Time) {
+	t.Helper()
+	if created.Before(before.Truncate(time.Millisecond)) || created.After(after) {
+		t.Fatalf("expected a time between %v and %v, got %v", before, after, created)
+	}
+}
+
+func TestCUID(t *testing.T) {
+	before := time.Now()
+	cuid := CUID()
+	after := time.Now()
+	if len(cuid) != cuidLength || !IsCUID(cuid) {
+		t.Fatalf("expected a CUID, got %s", cuid)
+	}
+	created, err := CUIDTime(cuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	between(t, created, before, after)
+}
+
+func TestCUIDTime(t *testing.T) {
+	for _, cuid := range []string{"", "cjsx2j8bw", "xjsx2j8bw02920b25rl806l07", "cjsx2j8b!02920b25rl806l07"} {
+		if _, err := CUIDTime(cuid); err != ErrInvalid {
+			t.Fatalf("expected ErrInvalid for %q, got %v", cuid, err)
+		}
+	}
+	want := time.Date(2019, 3, 8, 13, 56, 57, 436e6, time.UTC)
+	ms := want.UnixNano() / int64(time.Millisecond)
+	cuid := "c" + strconv.FormatInt(ms, base) + strings.Repeat("0", 4*blockSize)
+	created, err := CUIDTime(cuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, created.UTC())
+	}
+}
+
+func TestUUIDv4(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		b := bytes(t, UUIDv4())
+		if b[6]>>4 != 4 {
+			t.Fatalf("expected version 4, got %d", b[6]>>4)
+		}
+		if b[8]>>6 != 2 {
+			t.Fatalf("expected the RFC 4122 variant, got %b", b[8]>>6)
+		}
+	}
+}
+
+func TestUUIDv7(t *testing.T) {
+	before := time.Now()
+	uuids := make([]string, 10000)
+	for i := range uuids {
+		uuids[i] = UUIDv7()
+	}
+	after := time.Now()
+	for i, uuid := range uuids {
+		b := bytes(t, uuid)
+		if b[6]>>4 != 7 {
+			t.Fatalf("expected version 7, got %d", b[6]>>4)
+		}
+		if b[8]>>6 != 2 {
+			t.Fatalf("expected the RFC 4122 variant, got %b", b[8]>>6)
+		}
+		// many are created within the same millisecond
+		if i > 0 && uuid <= uuids[i-1] {
+			t.Fatalf("expected %s to sort after %s", uuid, uuids[i-1])
+		}
+	}
+	// running out of numbers within a millisecond moves on to the next
+	// one, which may be ahead of the clock
+	after = after.Add(time.Duration(len(uuids)/0x800) * time.Millisecond)
+	for _, uuid := range []string{uuids[0], uuids[len(uuids)-1]} {
+		created, err := UUIDTime(uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		between(t, created, before, after)
+	}
+}
+
+func TestUUIDTime(t *testing.T) {
+	for _, uuid := range []string{"", "not a uuid", UUIDv4(), "017f22e2-79b0-7cc3-98c4"} {
+		if _, err := UUIDTime(uuid); err != ErrInvalid {
+			t.Fatalf("expected ErrInvalid for %q, got %v", uuid, err)
+		}
+	}
+	created, err := UUIDTime("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Unix(0, 0x017f22e279b0*int64(time.Millisecond)); !created.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, created)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	const goroutines, n = 8, 1000
+	var (
+		mu   sync.Mutex
+		seen = map[string]bool{}
+		wg   sync.WaitGroup
+	)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]string, 0, 3*n)
+			for i := 0; i < n; i++ {
+				ids = append(ids, CUID(), UUIDv4(), UUIDv7())
+			}
+			mu.Lock()
+			for _, id := range ids {
+				seen[id] = true
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(seen) != goroutines*3*n {
+		t.Fatalf("expected %d different ids, got %d", goroutines*3*n, len(seen))
+	}
+}
@@ -0,0 +1,94 @@
+package prisma
+
+import (
+	"context"
+	"errors"
+)
+
+// DefaultBatchSize is the number of records an iterator fetches per query
+const DefaultBatchSize = 100
+
+// ErrIterateBackward is returned by iterators given Last or Before
+var ErrIterateBackward = errors.New("prisma: iterators only page forward, Last and Before aren't supported")
+
+// pager pages forward through records using the After cursor
+type pager struct {
+	ctx   context.Context
+	size  int
+	after *string
+	skip  *int
+	// limit is what's left of the caller's First, if any
+	limit *int
+	// requested is the size of the batch being fetched
+	requested int
+	done      bool
+	err       error
+}
+
+func newPager(ctx context.Context, c *conditions) pager {
+	p := pager{
+		ctx:   ctx,
+		size:  DefaultBatchSize,
+		after: c.after,
+		skip:  c.skip,
+		limit: c.first,
+	}
+	if c.last != nil || c.before != nil {
+		p.err = ErrIterateBackward
+	}
+	return p
+}
+
+// next returns the conditions for the next batch, or false when iteration
+// is over
+func (p *pager) next(base conditions) (*conditions, bool) {
+	if p.err != nil || p.done {
+		return nil, false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return nil, false
+	}
+	first := p.size
+	if p.limit != nil && *p.limit < first {
+		first = *p.limit
+	}
+	if first <= 0 {
+		p.done = true
+		return nil, false
+	}
+	p.requested = first
+	base.first = &first
+	base.after = p.after
+	base.skip = p.skip
+	return &base, true
+}
+
+// advance past a batch of n records that ended at cursor
+func (p *pager) advance(n int, cursor string, err error) {
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.skip = nil
+	p.after = &cursor
+	if p.limit != nil {
+		limit := *p.limit - n
+		p.limit = &limit
+	}
+	if n < p.requested {
+		p.done = true
+	}
+}
+
+// check the context before handing out another record
+func (p *pager) check() bool {
+	if p.err != nil {
+		return false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package prisma
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var (
+	firstArg = regexp.MustCompile(`first: (\d+)`)
+	afterArg = regexp.MustCompile(`after: "([^"]+)"`)
+)
+
+// pages answers findManyUser queries with a page of n users, u1 to un,
+// taking the first and after arguments into account like the engine
+func pages(n int) func(query string) string {
+	return func(query string) string {
+		start := 1
+		if m := afterArg.FindStringSubmatch(query); m != nil {
+			after, _ := strconv.Atoi(strings.TrimPrefix(m[1], "u"))
+			start = after + 1
+		}
+		end := n
+		if m := firstArg.FindStringSubmatch(query); m != nil {
+			first, _ := strconv.Atoi(m[1])
+			if start+first-1 < end {
+				end = start + first - 1
+			}
+		}
+		var users []string
+		for i := start; i <= end; i++ {
+			users = append(users, fmt.Sprintf(`{"id": "u%d"}`, i))
+		}
+		return "[" + strings.Join(users, ", ") + "]"
+	}
+}
+
+// iterate collects the ids of the users an iterator returns
+func iterate(t *testing.T, it *UserIterator) []string {
+	t.Helper()
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func TestIteratePages(t *testing.T) {
+	client, db := testClient(pages(5))
+	it := client.User.Iterate(context.Background(), (&UserOrder{}).Name(ASC)).BatchSize(2)
+	if ids := strings.Join(iterate(t, it), " "); ids != "u1 u2 u3 u4 u5" {
+		t.Fatalf("expected every user once, got %s", ids)
+	}
+	sent := db.sent()
+	if len(sent) != 3 {
+		t.Fatalf("expected 3 pages, got %v", sent)
+	}
+	for i, after := range []string{"", "u2", "u4"} {
+		query := sent[i]
+		// the cursor only pages correctly with the id tie-break
+		if !strings.Contains(query, "orderBy: [{name: ASC}, {id: ASC}]") || !strings.Contains(query, "first: 2") {
+			t.Fatalf("expected page %d ordered by name then id in %s", i, query)
+		}
+		if m := afterArg.FindStringSubmatch(query); (m == nil) != (after == "") || (m != nil && m[1] != after) {
+			t.Fatalf("expected page %d after %q in %s", i, after, query)
+		}
+	}
+}
+
+func TestIterateFullLastPage(t *testing.T) {
+	// a full last page takes another query to find out there's no more
+	client, db := testClient(pages(4))
+	it := client.User.Iterate(context.Background()).BatchSize(2)
+	if ids := strings.Join(iterate(t, it), " "); ids != "u1 u2 u3 u4" {
+		t.Fatalf("expected every user once, got %s", ids)
+	}
+	if sent := db.sent(); len(sent) != 3 || !strings.Contains(sent[2], `after: "u4"`) {
+		t.Fatalf("expected an empty page after u4, got %v", sent)
+	}
+}
+
+func TestIterateFirst(t *testing.T) {
+	client, db := testClient(pages(10))
+	first := UserFirst(3)
+	it := client.User.Iterate(context.Background(), &first).BatchSize(2)
+	if ids := strings.Join(iterate(t, it), " "); ids != "u1 u2 u3" {
+		t.Fatalf("expected the first 3 users, got %s", ids)
+	}
+	sent := db.sent()
+	if len(sent) != 2 || !strings.Contains(sent[1], "first: 1") {
+		t.Fatalf("expected the last page to only fetch what's left, got %v", sent)
+	}
+}
+
+func TestIterateBackward(t *testing.T) {
+	last := UserLast(2)
+	before := UserBefore("u3")
+	for _, c := range []UserCondition{&last, &before} {
+		client, db := testClient(pages(5))
+		it := client.User.Iterate(context.Background(), c)
+		if it.Next() {
+			t.Fatal("expected no users")
+		}
+		if it.Err() != ErrIterateBackward {
+			t.Fatalf("expected ErrIterateBackward, got %v", it.Err())
+		}
+		if len(db.sent()) != 0 {
+			t.Fatalf("expected nothing to be sent, got %v", db.sent())
+		}
+	}
+}
+
+func TestIterateCanceled(t *testing.T) {
+	client, db := testClient(pages(5))
+	ctx, cancel := context.WithCancel(context.Background())
+	it := client.User.Iterate(ctx).BatchSize(2)
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	cancel()
+	if it.Next() {
+		t.Fatal("expected the iteration to stop")
+	}
+	if it.Err() != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", it.Err())
+	}
+	if len(db.sent()) != 1 {
+		t.Fatalf("expected a single page, got %v", db.sent())
+	}
+}
@@ -0,0 +1,153 @@
+package prisma
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// null is how JSON spells a missing value
+var null = []byte("null")
+
+// NullString is a string that may be null
+type NullString struct {
+	String string
+	Valid  bool // Valid is true if String is not null
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.String)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullString{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.String)
+}
+
+// NullInt is an int that may be null
+type NullInt struct {
+	Int   int
+	Valid bool // Valid is true if Int is not null
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.Int)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullInt) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullInt{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.Int)
+}
+
+// NullFloat is a float that may be null
+type NullFloat struct {
+	Float float64
+	Valid bool // Valid is true if Float is not null
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullFloat) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.Float)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullFloat) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullFloat{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.Float)
+}
+
+// NullTime is a time that may be null
+type NullTime struct {
+	Time  time.Time
+	Valid bool // Valid is true if Time is not null
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullTime) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.Time)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullTime{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.Time)
+}
+
+// NullDecimal is a decimal that may be null
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool // Valid is true if Decimal is not null
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullDecimal) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.Decimal)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullDecimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullDecimal{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.Decimal)
+}
+
+// NullBigInt is a BigInt that may be null
+type NullBigInt struct {
+	BigInt BigInt
+	Valid  bool // Valid is true if BigInt is not null
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullBigInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.BigInt)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullBigInt) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullBigInt{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.BigInt)
+}
@@ -0,0 +1,205 @@
+package prisma
+
+import (
+	"context"
+	"sync"
+)
+
+// policyFunc is a policy with its filter type erased, it returns the
+// compiled filter
+type policyFunc func(ctx context.Context) (object, error)
+
+// createPolicyFunc is a create policy with its input type erased
+type createPolicyFunc func(ctx context.Context, input interface{}) error
+
+// policies are the row-level policies of a client. Like hooks, they're
+// shared by the copies of the client.
+type policies struct {
+	mu     sync.RWMutex
+	where  map[string][]policyFunc
+	create map[string][]createPolicyFunc
+}
+
+func newPolicies() *policies {
+	return &policies{
+		where:  map[string][]policyFunc{},
+		create: map[string][]createPolicyFunc{},
+	}
+}
+
+// add a policy on the records of model
+func (p *policies) add(model string, fn policyFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.where[model] = append(p.where[model], fn)
+}
+
+// addCreate adds a policy on the records created on model
+func (p *policies) addCreate(model string, fn createPolicyFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.create[model] = append(p.create[model], fn)
+}
+
+// restrict ANDs the filters of the policies into every filter of op, so
+// reads, updates and deletes only match the records the policies allow,
+// and the relations included, counted or ordered by only hold them. The
+// records created by nested writes are checked against the create
+// policies of their models.
+func (p *policies) restrict(ctx context.Context, op *operation) (*operation, error) {
+	kind, model, ok := splitOperation(op.name)
+	if !ok {
+		return op, nil
+	}
+	for _, name := range []string{"data", "create", "update"} {
+		if data, ok := op.args.get(name); ok {
+			if err := p.nested(ctx, model, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if kind.create() && len(op.related) == 0 {
+		return op, nil
+	}
+	return restrict(&policyScope{policies: p, ctx: ctx}, op)
+}
+
+// policyScope restricts a single operation to the records the policies
+// allow
+type policyScope struct {
+	*policies
+	ctx context.Context
+}
+
+// filter returns the filters of the policies of model ANDed together, nil
+// when it has none
+func (s *policyScope) filter(model string) (object, error) {
+	s.mu.RLock()
+	fns := s.where[model]
+	s.mu.RUnlock()
+	var filters []object
+	for _, fn := range fns {
+		filter, err := fn(s.ctx)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return and(filters...), nil
+}
+
+// nested checks the records created by the nested writes within the data
+// of a write on model against the create policies of their models
+func (p *policies) nested(ctx context.Context, model string, data interface{}) error {
+	_, err := each(data, func(data object) (object, error) {
+		for _, rule := range modelRules[model].fields {
+			value, _ := data.get(rule.field)
+			nested, _ := value.(object)
+			create, ok := nested.get("create")
+			if rule.relation == "" || !ok {
+				continue
+			}
+			_, err := each(create, func(row object) (object, error) {
+				if err := p.allow(ctx, rule.relation, newInput(rule.relation, row)); err != nil {
+					return nil, err
+				}
+				return row, p.nested(ctx, rule.relation, row)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return data, nil
+	})
+	return err
+}
+
+// newInput wraps the data of a record of model in the model's input, which
+// is what its create policies take
+func newInput(model string, data object) interface{} {
+	switch model {
+	case "User":
+		return &UserInput{data: data}
+	case "Post":
+		return &PostInput{data: data}
+	case "Comment":
+		return &CommentInput{data: data}
+	}
+	return nil
+}
+
+// allow checks input against the create policies of model
+func (p *policies) allow(ctx context.Context, model string, input interface{}) error {
+	p.mu.RLock()
+	fns := p.create[model]
+	p.mu.RUnlock()
+	for _, fn := range fns {
+		if err := fn(ctx, input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Policy registers a row-level policy on users. Its filter is ANDed into
+// every read, update and delete of users, so they only match the users it
+// allows, and into the users included with With, counted by Select or
+// ordered by count. Returning nil allows every user, returning an error
+// fails the query before it's sent.
+func (u *UserModel) Policy(fn func(ctx context.Context) (*UserWhere, error)) {
+	u.client.policies.add("User", func(ctx context.Context) (object, error) {
+		where, err := fn(ctx)
+		return where.filter(), err
+	})
+}
+
+// CreatePolicy registers a policy that's called with every user created
+// through Create, CreateMany, Upsert and nested creates. Returning an error
+// cancels the write.
+func (u *UserModel) CreatePolicy(fn func(ctx context.Context, user *UserInput) error) {
+	u.client.policies.addCreate("User", func(ctx context.Context, v interface{}) error {
+		return fn(ctx, v.(*UserInput))
+	})
+}
+
+// Policy registers a row-level policy on posts. Its filter is ANDed into
+// every read, update and delete of posts, so they only match the posts it
+// allows, and into the posts included with With, counted by Select or
+// ordered by count. Returning nil allows every post, returning an error
+// fails the query before it's sent.
+func (p *PostModel) Policy(fn func(ctx context.Context) (*PostWhere, error)) {
+	p.client.policies.add("Post", func(ctx context.Context) (object, error) {
+		where, err := fn(ctx)
+		return where.filter(), err
+	})
+}
+
+// CreatePolicy registers a policy that's called with every post created
+// through Create, CreateMany and nested creates. Returning an error cancels
+// the write.
+func (p *PostModel) CreatePolicy(fn func(ctx context.Context, post *PostInput) error) {
+	p.client.policies.addCreate("Post", func(ctx context.Context, v interface{}) error {
+		return fn(ctx, v.(*PostInput))
+	})
+}
+
+// Policy registers a row-level policy on comments. Its filter is ANDed into
+// every read, update and delete of comments, so they only match the
+// comments it allows, and into the comments included with With, counted by
+// Select or ordered by count. Returning nil allows every comment,
+// returning an error fails the query before it's sent.
+func (c *CommentModel) Policy(fn func(ctx context.Context) (*CommentWhere, error)) {
+	c.client.policies.add("Comment", func(ctx context.Context) (object, error) {
+		where, err := fn(ctx)
+		return where.filter(), err
+	})
+}
+
+// CreatePolicy registers a policy that's called with every comment created
+// through Create, CreateMany and nested creates. Returning an error cancels
+// the write.
+func (c *CommentModel) CreatePolicy(fn func(ctx context.Context, comment *CommentInput) error) {
+	c.client.policies.addCreate("Comment", func(ctx context.Context, v interface{}) error {
+		return fn(ctx, v.(*CommentInput))
+	})
+}
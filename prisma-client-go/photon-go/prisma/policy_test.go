@@ -0,0 +1,110 @@
+package prisma
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// policyClient returns a client whose posts are only the published ones
+func policyClient(respond func(query string) string) (*Client, *testDB) {
+	client, db := testClient(respond)
+	client.Post.Policy(func(ctx context.Context) (*PostWhere, error) {
+		return (&PostWhere{}).Published(true), nil
+	})
+	return client, db
+}
+
+func TestPolicyWith(t *testing.T) {
+	client, db := policyClient(func(string) string { return `[]` })
+	if _, err := client.User.FindMany((&UserWith{}).Posts()); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	if !strings.Contains(query, `posts(where: {AND: [{deletedAt: null}, {published: true}]})`) {
+		t.Fatalf("expected the included posts to be restricted in %s", query)
+	}
+	if strings.Contains(query, "findManyUser(where") {
+		t.Fatalf("expected users to be unrestricted in %s", query)
+	}
+}
+
+func TestPolicySelectCount(t *testing.T) {
+	client, db := policyClient(func(string) string { return `[]` })
+	var users []struct {
+		ID         string
+		PostsCount int
+	}
+	if err := client.User.Select(&users); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	if !strings.Contains(query, `PostsCount: _count { posts(where: {AND: [{deletedAt: null}, {published: true}]}) }`) {
+		t.Fatalf("expected the counted posts to be restricted in %s", query)
+	}
+}
+
+func TestPolicyOrderByCount(t *testing.T) {
+	client, db := policyClient(func(string) string { return `[]` })
+	if _, err := client.User.FindMany((&UserOrder{}).PostsCount(DESC)); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	if !strings.Contains(query, `{posts: {count: DESC, where: {published: true}}}`) {
+		t.Fatalf("expected the ordering posts to be restricted in %s", query)
+	}
+}
+
+func TestPolicyNestedCreate(t *testing.T) {
+	client, db := testClient(func(string) string { return `{"id": "u1"}` })
+	denied := errors.New("denied")
+	var titles []string
+	client.Post.CreatePolicy(func(ctx context.Context, post *PostInput) error {
+		title, _ := post.data.get("title")
+		titles = append(titles, title.(string))
+		if title == "draft" {
+			return denied
+		}
+		return nil
+	})
+	user := (&UserInput{}).Email("ada@prisma.io").CreatePosts((&PostInput{}).Title("hello"))
+	if _, err := client.User.Create(user); err != nil {
+		t.Fatal(err)
+	}
+	if len(titles) != 1 || titles[0] != "hello" {
+		t.Fatalf("expected the nested post to be checked, got %v", titles)
+	}
+	// nested creates within updates are checked too
+	_, err := client.User.Update((&UserInput{}).CreatePosts((&PostInput{}).Title("draft")), (&UserWhere{}).ID("u1"))
+	if err != denied {
+		t.Fatalf("expected the policy's error, got %v", err)
+	}
+	if len(db.sent()) != 1 {
+		t.Fatalf("expected the update not to be sent, got %v", db.sent())
+	}
+}
+
+func TestPolicyUnique(t *testing.T) {
+	client, db := policyClient(func(query string) string {
+		if strings.Contains(query, "aggregate") {
+			return `{"count": 0}`
+		}
+		return `{"id": "p1"}`
+	})
+	// posts that aren't published can't be updated
+	if _, err := client.Post.Update((&PostInput{}).Title("a"), (&PostWhere{}).ID("p1")); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	sent := db.sent()
+	if len(sent) != 1 || !strings.Contains(sent[0], `aggregatePost(where: {AND: [{id: "p1"}, {published: true}]}) { count }`) {
+		t.Fatalf("expected only a restricted count, got %v", sent)
+	}
+	// users have no policy, so their unique writes are sent as they are
+	if _, err := client.User.Update((&UserInput{}).Name("Ada"), (&UserWhere{}).ID("u1")); err != nil {
+		t.Fatal(err)
+	}
+	if sent := db.sent(); len(sent) != 2 || !strings.Contains(sent[1], `updateOneUser(data: {name: "Ada"}, where: {id: "u1"})`) {
+		t.Fatalf("expected the update alone, got %v", sent)
+	}
+}
@@ -6,12 +6,18 @@ import (
 	"github.com/prisma/specs/photongo/photon-go/prisma"
 )
 
+// ID field
+type ID string
+
 // Title field
 type Title string
 
 // CreatedAt field
 type CreatedAt time.Time
 
+// CommentsCount field, the number of comments on the post
+type CommentsCount int
+
 // Post model
 type Post struct {
 }
@@ -30,11 +36,72 @@ func New() *prisma.PostInput {
 // type condition struct {
 // }
 
+// Field enum
+var Field = struct {
+	ID          prisma.PostField
+	CreatedAt   prisma.PostField
+	UpdatedAt   prisma.PostField
+	Title       prisma.PostField
+	Published   prisma.PostField
+	Views       prisma.PostField
+	Impressions prisma.PostField
+	Metadata    prisma.PostField
+	Version     prisma.PostField
+	DeletedAt   prisma.PostField
+}{
+	ID:          "id",
+	CreatedAt:   "createdAt",
+	UpdatedAt:   "updatedAt",
+	Title:       "title",
+	Published:   "published",
+	Views:       "views",
+	Impressions: "impressions",
+	Metadata:    "metadata",
+	Version:     "version",
+	DeletedAt:   "deletedAt",
+}
+
+// Having condition on grouped posts
+func Having() *prisma.PostHaving {
+	return &prisma.PostHaving{}
+}
+
+// GroupOrder condition on grouped posts
+func GroupOrder() *prisma.PostGroupOrder {
+	return &prisma.PostGroupOrder{}
+}
+
+// Distinct condition, returns one post for each distinct set of fields
+func Distinct(fields ...prisma.PostField) *prisma.PostDistinct {
+	c := prisma.PostDistinct(fields)
+	return &c
+}
+
 // Where condition
 func Where() *prisma.PostWhere {
 	return &prisma.PostWhere{}
 }
 
+// If requires the conditions to still hold when updating the post
+func If(conditions ...*prisma.PostWhere) *prisma.PostWhere {
+	return (&prisma.PostWhere{}).If(conditions...)
+}
+
+// IfVersion requires the post to still be at version when updating it
+func IfVersion(version int) *prisma.PostWhere {
+	return (&prisma.PostWhere{}).IfVersion(version)
+}
+
+// WithDeleted condition, matches the deleted posts along with the others
+func WithDeleted() *prisma.PostWhere {
+	return (&prisma.PostWhere{}).WithDeleted()
+}
+
+// OnlyDeleted condition, only matches the deleted posts
+func OnlyDeleted() *prisma.PostWhere {
+	return (&prisma.PostWhere{}).OnlyDeleted()
+}
+
 // Connect condition
 func Connect() *prisma.PostConnect {
 	return &prisma.PostConnect{}
@@ -42,32 +109,52 @@ func Connect() *prisma.PostConnect {
 
 // First condition
 func First(first int) *prisma.PostFirst {
-	return &prisma.PostFirst{}
+	c := prisma.PostFirst(first)
+	return &c
 }
 
 // After condition
 func After(after string) *prisma.PostAfter {
-	return &prisma.PostAfter{}
+	c := prisma.PostAfter(after)
+	return &c
 }
 
 // Before condition
 func Before(before string) *prisma.PostBefore {
-	return &prisma.PostBefore{}
+	c := prisma.PostBefore(before)
+	return &c
 }
 
 // Skip condition
 func Skip(skip int) *prisma.PostSkip {
-	return &prisma.PostSkip{}
+	c := prisma.PostSkip(skip)
+	return &c
 }
 
 // Last condition
 func Last(last int) *prisma.PostLast {
-	return &prisma.PostLast{}
+	c := prisma.PostLast(last)
+	return &c
+}
+
+// Order condition
+func Order() *prisma.PostOrder {
+	return &prisma.PostOrder{}
+}
+
+// WithAuthor includes the author of the post
+func WithAuthor(with ...*prisma.UserWith) *prisma.PostWith {
+	return (&prisma.PostWith{}).Author(with...)
 }
 
 // WithComments comments
-func WithComments(conditions ...*prisma.CommentCondition) *prisma.PostWith {
-	return nil
+func WithComments(conditions ...prisma.CommentCondition) *prisma.PostWith {
+	return (&prisma.PostWith{}).Comments(conditions...)
+}
+
+// WithCommentsCount filters the comments counted into CommentsCount
+func WithCommentsCount(where ...*prisma.CommentWhere) *prisma.PostWith {
+	return (&prisma.PostWith{}).CommentsCount(where...)
 }
 
 // Find a post by a condition
@@ -91,7 +178,7 @@ func Update(db prisma.Client, post *prisma.PostInput, where *prisma.PostWhere) (
 }
 
 // UpdateMany a posts
-func UpdateMany(db prisma.Client, post *prisma.PostInput, where *prisma.PostWhere) (*prisma.Post, error) {
+func UpdateMany(db prisma.Client, post *prisma.PostInput, where *prisma.PostWhere) (*prisma.BatchPayload, error) {
 	return nil, nil
 }
 
@@ -101,7 +188,7 @@ func Delete(db prisma.Client, where *prisma.PostWhere) (*prisma.Post, error) {
 }
 
 // DeleteMany posts
-func DeleteMany(db prisma.Client, where *prisma.PostWhere) (*prisma.Post, error) {
+func DeleteMany(db prisma.Client, where *prisma.PostWhere) (*prisma.BatchPayload, error) {
 	return nil, nil
 }
 
@@ -1,9 +1,14 @@
 package prisma
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
+	"net/http"
 	uri "net/url"
 	"os/exec"
 	"reflect"
@@ -17,10 +22,7 @@ func New(url string) *Client {
 		URL:   url,
 		Debug: false,
 	}
-	return &Client{
-		User: &UserModel{db: http},
-		Post: &PostModel{db: http},
-	}
+	return newClient(http)
 }
 
 // Dial a remote TCP Prisma Engine
@@ -36,10 +38,7 @@ func Dial(url string) (*Client, error) {
 	db := &TCP{
 		conn: conn,
 	}
-	return &Client{
-		User: &UserModel{db: db},
-		Post: &PostModel{db: db},
-	}, nil
+	return newClient(db), nil
 }
 
 const defaultEnginePath = ""
@@ -56,10 +55,7 @@ func Connect() (*Client, error) {
 		cmd:   cmd,
 		stdin: w,
 	}
-	return &Client{
-		User: &UserModel{db: process},
-		Post: &PostModel{db: process},
-	}, nil
+	return newClient(process), nil
 }
 
 // Launch a Prisma Engine and connect to it
@@ -74,10 +70,7 @@ func Launch(path string, args ...string) (*Client, error) {
 		cmd:   cmd,
 		stdin: w,
 	}
-	return &Client{
-		User: &UserModel{db: process},
-		Post: &PostModel{db: process},
-	}, nil
+	return newClient(process), nil
 }
 
 // DB interface
@@ -90,15 +83,65 @@ type DB interface {
 type HTTP struct {
 	URL   string
 	Debug bool
+
+	// tx is the id of the transaction the queries are sent in, if any
+	tx string
 }
 
 var _ DB = (*HTTP)(nil)
 
 // Send a query to the Prisma Engine and wait for a result
 func (c *HTTP) Send(ctx context.Context, query string, result interface{}) error {
-	// TODO: write to the connection and wait for a response
-	// c.conn.Write([]byte(query))
-	return nil
+	body, err := c.Stream(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	var payload struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []*EngineError  `json:"errors"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return err
+	}
+	if len(payload.Errors) > 0 {
+		return payload.Errors[0]
+	}
+	if result == nil || len(payload.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(payload.Data, result)
+}
+
+var _ Streamer = (*HTTP)(nil)
+
+// Stream a query's response from the Prisma Engine. The caller must close
+// the response. Responses with a status other than 2xx are errors.
+func (c *HTTP) Stream(ctx context.Context, query string) (io.ReadCloser, error) {
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.tx != "" {
+		req.Header.Set("X-Transaction-Id", c.tx)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// an engine or proxy failing may not answer with JSON at all, so the
+	// start of the body is reported along with the status
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		start, _ := ioutil.ReadAll(io.LimitReader(res.Body, 512))
+		return nil, fmt.Errorf("prisma: engine returned %s: %s", res.Status, bytes.TrimSpace(start))
+	}
+	return res.Body, nil
 }
 
 // Close does nothing because HTTP is stateless
@@ -160,71 +203,331 @@ const (
 	DESC         = "DESC"
 )
 
+func (o OrderBy) enum() string {
+	return string(o)
+}
+
+// nulls is where null values are sorted
+type nulls string
+
+func (n nulls) enum() string {
+	return string(n)
+}
+
+const (
+	nullsFirst nulls = "first"
+	nullsLast  nulls = "last"
+)
+
 // Client struct
 type Client struct {
 	ctx context.Context
+	db  DB
+	// cache of lazily loaded relations, only set on request-scoped clients
+	cache *cache
+	// tx is set on clients returned by Transaction
+	tx bool
+	// hooks and policies are shared with the copies of the client
+	hooks    *hooks
+	policies *policies
+	// clock tells the time of timestamps, the system's time when nil
+	clock Clock
+	// tenancy scopes queries to the tenant of their context
+	tenancy *Tenancy
 
 	User    *UserModel
 	Post    *PostModel
 	Comment *CommentModel
 }
 
-// WithContext fn
-func (c *Client) WithContext(ctx context.Context) *Client {
-	c.ctx = ctx
+// newClient wires up the models to a DB
+func newClient(db DB) *Client {
+	c := &Client{db: db, hooks: newHooks(), policies: newPolicies()}
+	return c.bind()
+}
+
+// bind the models to this client
+func (c *Client) bind() *Client {
+	c.User = &UserModel{client: c}
+	c.Post = &PostModel{client: c}
+	c.Comment = &CommentModel{client: c}
 	return c
 }
 
+// WithContext returns a copy of the client that sends its queries with ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	client := *c
+	client.ctx = ctx
+	client.cache = newCache()
+	return client.bind()
+}
+
 // Disconnect fn
 func (c *Client) Disconnect() error {
 	return nil
 }
 
+// Context returns the context the client sends its queries with
+func (c *Client) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// send an operation to the engine and decode its result into v
+func (c *Client) send(op *operation, v interface{}) error {
+	return c.sendContext(c.Context(), op, v)
+}
+
+// sendContext is like send, but with an explicit context
+func (c *Client) sendContext(ctx context.Context, op *operation, v interface{}) error {
+	if op.mutation {
+		c.cache.reset()
+	}
+	if c.unverified(op) {
+		return c.WithContext(ctx).atomically(func(c *Client) error {
+			return c.sendVerified(ctx, op, v)
+		})
+	}
+	return c.sendCompiled(ctx, op, v)
+}
+
+// sendCompiled compiles op and sends it as it is
+func (c *Client) sendCompiled(ctx context.Context, op *operation, v interface{}) error {
+	query, err := c.compile(ctx, op)
+	if err != nil {
+		return err
+	}
+	result, err := c.fetch(ctx, query)
+	if err != nil {
+		return err
+	}
+	return c.decode(result, v)
+}
+
+// load is like send, but repeated loads within a request are only sent to
+// the engine once
+func (c *Client) load(ctx context.Context, op *operation, v interface{}) error {
+	query, err := c.compile(ctx, op)
+	if err != nil {
+		return err
+	}
+	result, ok := c.cache.get(query)
+	if !ok {
+		var err error
+		if result, err = c.fetch(ctx, query); err != nil {
+			return err
+		}
+		c.cache.set(query, result)
+	}
+	return c.decode(result, v)
+}
+
+// fetch the raw result of a query
+func (c *Client) fetch(ctx context.Context, query string) (json.RawMessage, error) {
+	var data struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := c.db.Send(ctx, query, &data); err != nil {
+		return nil, err
+	}
+	return data.Result, nil
+}
+
+// decode a result into v and attach the returned models to the client
+func (c *Client) decode(result json.RawMessage, v interface{}) error {
+	if v == nil || len(result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(result, v); err != nil {
+		return err
+	}
+	attach(c, reflect.ValueOf(v))
+	return nil
+}
+
+// attacher is implemented by models that can lazily load their relations
+type attacher interface {
+	attach(c *Client)
+}
+
+// attach every model within v to the client
+func attach(c *Client, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if a, ok := v.Interface().(attacher); ok {
+			a.attach(c)
+			return
+		}
+		attach(c, v.Elem())
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			attach(c, v.Index(i))
+		}
+	}
+}
+
 // UserModel struct
 type UserModel struct {
-	db DB
+	client *Client
+	// scope is set when the model is reached through As
+	scope object
 }
 
 // User struct
 type User struct {
-	ID    string
-	Name  string
-	Email string
+	ID      string     `json:"id"`
+	Name    NullString `json:"name"`
+	Email   string     `json:"email"`
+	Role    UserRole   `json:"role"`
+	Balance Decimal    `json:"balance"`
+	Avatar  []byte     `json:"avatar"` // nil when null
+
+	// Relations are only filled in when included with With
+	Relations UserRelations `json:"-"`
+
+	// client that returned the user, used to lazily load relations
+	client *Client
+}
+
+// UserRelations included with user.WithPosts and user.WithComments. A nil
+// relation wasn't included, while an included relation without any records
+// is an empty slice.
+type UserRelations struct {
+	Posts    []*Post    `json:"posts"`
+	Comments []*Comment `json:"comments"`
+}
+
+// UnmarshalJSON decodes a user along with its included relations
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	if err := json.Unmarshal(data, (*user)(u)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &u.Relations)
+}
+
+func (u *User) attach(c *Client) {
+	u.client = c
+	for _, post := range u.Relations.Posts {
+		post.attach(c)
+	}
+	for _, comment := range u.Relations.Comments {
+		comment.attach(c)
+	}
+}
+
+// Posts loads the user's posts. Posts included with user.WithPosts are
+// returned without a query when there are no conditions.
+func (u *User) Posts(ctx context.Context, conditions ...PostCondition) ([]*Post, error) {
+	if u.Relations.Posts != nil && len(conditions) == 0 {
+		return u.Relations.Posts, nil
+	}
+	if u.client == nil {
+		return nil, ErrDetached
+	}
+	model := &PostModel{client: u.client, scope: object{{"author", object{{"id", u.ID}}}}}
+	var posts []*Post
+	if err := u.client.load(ctx, model.findMany(mergePostConditions(conditions)), &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
 }
 
+// Comments loads the comments written by the user. Comments included with
+// user.WithComments are returned without a query when there are no conditions.
+func (u *User) Comments(ctx context.Context, conditions ...CommentCondition) ([]*Comment, error) {
+	if u.Relations.Comments != nil && len(conditions) == 0 {
+		return u.Relations.Comments, nil
+	}
+	if u.client == nil {
+		return nil, ErrDetached
+	}
+	model := &CommentModel{client: u.client, scope: object{{"writtenBy", object{{"id", u.ID}}}}}
+	var comments []*Comment
+	if err := u.client.load(ctx, model.findMany(mergeCommentConditions(conditions)), &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// userFields are selected when returning users
+const userFields = "id name email role balance avatar"
+
 // UserID strings
 type UserID string
 
-//UserEmail strings
+// UserEmail strings
 type UserEmail string
 
 // As user, find a nested relation
 func (u *UserModel) As(where *UserWhere) *UserAs {
-	return &UserAs{}
+	scope := and(u.scope, where.filter())
+	return &UserAs{
+		Post:    &PostModel{client: u.client, scope: object{{"author", scope}}},
+		Comment: &CommentModel{client: u.client, scope: object{{"writtenBy", scope}}},
+	}
 }
 
 // Find a user by a condition
 func (u *UserModel) Find(conditions ...UserCondition) (user *User, err error) {
-	return user, err
+	c := mergeUserConditions(conditions)
+	if c.first == nil && c.last == nil {
+		one := 1
+		c.first = &one
+	}
+	var users []*User
+	if err := u.client.send(u.findMany(c), &users); err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, ErrNotFound
+	}
+	return users[0], nil
 }
 
 // Upsert a user by some conditions
 func (u *UserModel) Upsert(insert *UserInput, update *UserInput, where ...*UserWhere) (user *User, err error) {
-	return user, err
+	if u.scope != nil {
+		return nil, ErrScoped
+	}
+	if err := userRules.validate(insert.input(), true); err != nil {
+		return nil, err
+	}
+	if err := userRules.validate(update.input(), false); err != nil {
+		return nil, err
+	}
+	insert = &UserInput{data: userRules.stamp(insert.input(), true, u.client.now())}
+	if err := u.client.policies.allow(u.client.Context(), "User", insert); err != nil {
+		return nil, err
+	}
+	unique, _ := mergeUserWhere(where)
+	op := &operation{
+		mutation: true,
+		name:     "upsertOneUser",
+		args: object{
+			{"where", unique},
+			{"create", insert.input()},
+			{"update", userRules.stamp(userRules.versioned(update.input()), false, u.client.now())},
+		},
+		selection: userFields,
+	}
+	if err := u.client.send(op, &user); err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
-// Select a user by a condition
+// Select the users matching the conditions into v, a pointer to a struct or
+// a slice of structs whose fields are named after the user's fields and
+// relations. A PostsCount or CommentsCount field holds the number of
+// related records.
 func (u *UserModel) Select(v interface{}, conditions ...UserCondition) (err error) {
-	val := reflect.ValueOf(v).Elem()
-
-	for i := 0; i < val.NumField(); i++ {
-		// valueField := val.Field(i)
-		// typeField := reflect.TypeOf(valueField)
-		// tag := typeField.Tag
-		// fmt.Printf("Field Name: %s,\t Field Type: %v\n", typeField.Name, typeField.String())
-	}
-
-	return err
+	return u.client.selectInto(userSchema, u.scope, &mergeUserConditions(conditions).conditions, v)
 }
 
 func unroll(myvar interface{}) string {
@@ -237,32 +540,352 @@ func unroll(myvar interface{}) string {
 
 // FindMany users by a condition
 func (u *UserModel) FindMany(conditions ...UserCondition) (users []*User, err error) {
-	return users, err
+	if err := u.client.send(u.findMany(mergeUserConditions(conditions)), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (u *UserModel) findMany(c *userCondition) *operation {
+	return &operation{
+		name:      "findManyUser",
+		args:      c.args(u.scope),
+		selection: userFields,
+		related:   c.related(),
+	}
+}
+
+// FindForUpdate finds the users matching the conditions and locks them
+// against updates until the transaction ends. It fails with
+// ErrNoTransaction outside of Transaction.
+func (u *UserModel) FindForUpdate(conditions ...UserCondition) ([]*User, error) {
+	return (&UserLocking{model: u}).FindForUpdate(conditions...)
+}
+
+// FindForShare is like FindForUpdate, but other transactions can still
+// lock the users for sharing
+func (u *UserModel) FindForShare(conditions ...UserCondition) ([]*User, error) {
+	return (&UserLocking{model: u}).FindForShare(conditions...)
+}
+
+// SkipLocked leaves out the users that are already locked, so concurrent
+// workers each claim different users
+func (u *UserModel) SkipLocked() *UserLocking {
+	return &UserLocking{model: u, wait: lockSkipLocked}
+}
+
+// NoWait fails instead of waiting when a user is already locked
+func (u *UserModel) NoWait() *UserLocking {
+	return &UserLocking{model: u, wait: lockNoWait}
+}
+
+// UserLocking is a chaining element for locking users
+type UserLocking struct {
+	model *UserModel
+	wait  lockWait
 }
 
-// Create a user
+// FindForUpdate finds the users matching the conditions and locks them
+// against updates until the transaction ends
+func (l *UserLocking) FindForUpdate(conditions ...UserCondition) ([]*User, error) {
+	return l.find(lockUpdate, conditions)
+}
+
+// FindForShare finds the users matching the conditions and locks them
+// against updates, but not other shared locks, until the transaction ends
+func (l *UserLocking) FindForShare(conditions ...UserCondition) ([]*User, error) {
+	return l.find(lockShare, conditions)
+}
+
+func (l *UserLocking) find(mode lockMode, conditions []UserCondition) (users []*User, err error) {
+	op, err := l.model.client.lock(l.model.findMany(mergeUserConditions(conditions)), mode, l.wait)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.model.client.send(op, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FindEach calls fn with each user matching the conditions as it's
+// decoded, without holding the whole result in memory. It stops at the first
+// error returned by fn and returns it.
+func (u *UserModel) FindEach(ctx context.Context, fn func(*User) error, conditions ...UserCondition) error {
+	op := u.findMany(mergeUserConditions(conditions))
+	return u.client.stream(ctx, op, func(record json.RawMessage) error {
+		user := new(User)
+		if err := json.Unmarshal(record, user); err != nil {
+			return err
+		}
+		user.attach(u.client)
+		return fn(user)
+	})
+}
+
+// Stream the users matching the conditions over a channel. The channel
+// is unbuffered, so the users are only decoded as fast as they're
+// received. Cancel the context to stop early. The error channel receives the
+// result once the users channel is closed.
+func (u *UserModel) Stream(ctx context.Context, conditions ...UserCondition) (<-chan *User, <-chan error) {
+	users := make(chan *User)
+	errc := make(chan error, 1)
+	go func() {
+		err := u.FindEach(ctx, func(user *User) error {
+			select {
+			case users <- user:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}, conditions...)
+		close(users)
+		errc <- err
+		close(errc)
+	}()
+	return users, errc
+}
+
+// Iterate over the users matching the conditions. Users are fetched
+// in batches using the After cursor, keeping the order of the conditions.
+func (u *UserModel) Iterate(ctx context.Context, conditions ...UserCondition) *UserIterator {
+	c := mergeUserConditions(conditions)
+	return &UserIterator{
+		model: u,
+		cond:  c.conditions,
+		pager: newPager(ctx, &c.conditions),
+	}
+}
+
+// UserIterator iterates over users in batches
+type UserIterator struct {
+	model *UserModel
+	cond  conditions
+	pager pager
+	batch []*User
+	value *User
+}
+
+// BatchSize sets how many users are fetched per query
+func (it *UserIterator) BatchSize(size int) *UserIterator {
+	it.pager.size = size
+	return it
+}
+
+// Next advances to the next user, fetching another batch when needed.
+// It returns false when there are no more users, the context is done or
+// a query fails.
+func (it *UserIterator) Next() bool {
+	if !it.pager.check() {
+		return false
+	}
+	if len(it.batch) == 0 {
+		c, ok := it.pager.next(it.cond)
+		if !ok {
+			return false
+		}
+		var users []*User
+		err := it.model.client.sendContext(it.pager.ctx, it.model.findMany(&userCondition{*c}), &users)
+		cursor := ""
+		if len(users) > 0 {
+			cursor = users[len(users)-1].ID
+		}
+		it.pager.advance(len(users), cursor, err)
+		if err != nil || len(users) == 0 {
+			return false
+		}
+		it.batch = users
+	}
+	it.value, it.batch = it.batch[0], it.batch[1:]
+	return true
+}
+
+// Value returns the current user
+func (it *UserIterator) Value() *User {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *UserIterator) Err() error {
+	return it.pager.err
+}
+
+// Count the users matching the conditions
+func (u *UserModel) Count(where ...*UserWhere) (int64, error) {
+	var result BatchPayload
+	op := &operation{
+		name:      "aggregateUser",
+		args:      whereArgs(u.scope, andUserWhere(where)),
+		selection: "count",
+	}
+	if err := u.client.send(op, &result); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
+// Create a user. The user's create hooks run around it.
 func (u *UserModel) Create(user *UserInput) (*User, error) {
-	return nil, nil
+	if u.scope != nil {
+		return nil, ErrScoped
+	}
+	if err := userRules.validate(user.input(), true); err != nil {
+		return nil, err
+	}
+	// the generated fields are filled in first, so the hooks see them
+	user = &UserInput{data: userRules.stamp(user.input(), true, u.client.now())}
+	if err := u.client.policies.allow(u.client.Context(), "User", user); err != nil {
+		return nil, err
+	}
+	result, err := u.client.hooked("User", hookCreate, user, func(c *Client) (interface{}, error) {
+		return c.User.create(user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*User), nil
+}
+
+func (u *UserModel) create(user *UserInput) (*User, error) {
+	op := &operation{
+		mutation:  true,
+		name:      "createOneUser",
+		args:      object{{"data", user.input()}},
+		selection: userFields,
+	}
+	var result *User
+	if err := u.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateMany creates users in bulk and reports how many were created
+func (u *UserModel) CreateMany(ctx context.Context, users []*UserInput, opts *CreateManyOptions) (*BatchPayload, error) {
+	if u.scope != nil {
+		return nil, ErrScoped
+	}
+	rows := make([]object, len(users))
+	for i, user := range users {
+		rows[i] = user.input()
+	}
+	if err := userRules.validateRows(rows); err != nil {
+		return nil, err
+	}
+	rows = userRules.stampRows(rows, u.client.now())
+	for _, row := range rows {
+		if err := u.client.policies.allow(ctx, "User", &UserInput{data: row}); err != nil {
+			return nil, err
+		}
+	}
+	return u.client.createMany(ctx, "User", userRules.unique, rows, opts)
 }
 
-// Update a user
+// Update a user. The user's update hooks run around it.
 func (u *UserModel) Update(user *UserInput, where ...*UserWhere) (*User, error) {
-	return nil, nil
+	if u.scope != nil {
+		return nil, ErrScoped
+	}
+	if err := userRules.validate(user.input(), false); err != nil {
+		return nil, err
+	}
+	result, err := u.client.hooked("User", hookUpdate, user, func(c *Client) (interface{}, error) {
+		return c.User.update(user, where)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*User), nil
+}
+
+func (u *UserModel) update(user *UserInput, where []*UserWhere) (*User, error) {
+	data := userRules.stamp(userRules.versioned(user.input()), false, u.client.now())
+	unique, cond := mergeUserWhere(where)
+	if len(cond) > 0 {
+		var result *User
+		err := u.client.atomically(func(c *Client) (err error) {
+			if err := c.updateIf("User", data, unique, cond); err != nil {
+				return err
+			}
+			result, err = c.User.Find(&UserWhere{f: merge(unique, pick(data, userRules.unique))})
+			return err
+		})
+		return result, err
+	}
+	op := &operation{
+		mutation:  true,
+		name:      "updateOneUser",
+		args:      object{{"data", data}, {"where", unique}},
+		selection: userFields,
+	}
+	var result *User
+	if err := u.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-// UpdateMany a user
-func (u *UserModel) UpdateMany(user *UserInput, where ...*UserWhere) ([]*User, error) {
-	return nil, nil
+// UpdateMany updates every user matching the conditions and reports how
+// many were updated
+func (u *UserModel) UpdateMany(user *UserInput, where ...*UserWhere) (*BatchPayload, error) {
+	if err := userRules.validate(user.input(), false); err != nil {
+		return nil, err
+	}
+	op := &operation{
+		mutation:  true,
+		name:      "updateManyUser",
+		args:      whereArgs(u.scope, andUserWhere(where)).set("data", userRules.stamp(userRules.versioned(user.input()), false, u.client.now())),
+		selection: "count",
+	}
+	var result BatchPayload
+	if err := u.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
-// Delete a user
+// Delete a user. The user's delete hooks run around it.
 func (u *UserModel) Delete(where *UserWhere) (*User, error) {
-	return nil, nil
+	if u.scope != nil {
+		return nil, ErrScoped
+	}
+	result, err := u.client.hooked("User", hookDelete, where, func(c *Client) (interface{}, error) {
+		return c.User.delete(where)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*User), nil
+}
+
+func (u *UserModel) delete(where *UserWhere) (*User, error) {
+	op := &operation{
+		mutation:  true,
+		name:      "deleteOneUser",
+		args:      object{{"where", where.filter()}},
+		selection: userFields,
+	}
+	var result *User
+	if err := u.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-// DeleteMany a user
-func (u *UserModel) DeleteMany(where *UserWhere) ([]*User, error) {
-	return nil, nil
+// DeleteMany deletes every user matching the condition and reports how
+// many were deleted
+func (u *UserModel) DeleteMany(where *UserWhere) (*BatchPayload, error) {
+	op := &operation{
+		mutation:  true,
+		name:      "deleteManyUser",
+		args:      whereArgs(u.scope, where.filter()),
+		selection: "count",
+	}
+	var result BatchPayload
+	if err := u.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 //
@@ -271,16 +894,91 @@ func (u *UserModel) DeleteMany(where *UserWhere) ([]*User, error) {
 
 // UserInput struct
 type UserInput struct {
+	data object
+}
+
+func (i *UserInput) input() object {
+	if i == nil {
+		return object{}
+	}
+	return i.data
+}
+
+// ID sets the id of a new user, which is generated when it isn't set
+func (i *UserInput) ID(id string) *UserInput {
+	out := *i
+	out.data = out.data.set("id", id)
+	return &out
 }
 
 // Name UserInput
 func (i *UserInput) Name(name string) *UserInput {
-	return i
+	out := *i
+	out.data = out.data.set("name", name)
+	return &out
+}
+
+// NameSetNull sets the user's name to null
+func (i *UserInput) NameSetNull() *UserInput {
+	out := *i
+	out.data = out.data.set("name", nil)
+	return &out
 }
 
 // Email UserInput
 func (i *UserInput) Email(email string) *UserInput {
-	return i
+	out := *i
+	out.data = out.data.set("email", email)
+	return &out
+}
+
+// Balance UserInput
+func (i *UserInput) Balance(balance Decimal) *UserInput {
+	out := *i
+	out.data = out.data.set("balance", balance)
+	return &out
+}
+
+// BalanceIncrement atomically adds n to the balance
+func (i *UserInput) BalanceIncrement(n Decimal) *UserInput {
+	out := *i
+	out.data = out.data.set("balance", object{{"increment", n}})
+	return &out
+}
+
+// BalanceDecrement atomically subtracts n from the balance
+func (i *UserInput) BalanceDecrement(n Decimal) *UserInput {
+	out := *i
+	out.data = out.data.set("balance", object{{"decrement", n}})
+	return &out
+}
+
+// BalanceMultiply atomically multiplies the balance by n
+func (i *UserInput) BalanceMultiply(n Decimal) *UserInput {
+	out := *i
+	out.data = out.data.set("balance", object{{"multiply", n}})
+	return &out
+}
+
+// BalanceDivide atomically divides the balance by n
+func (i *UserInput) BalanceDivide(n Decimal) *UserInput {
+	out := *i
+	out.data = out.data.set("balance", object{{"divide", n}})
+	return &out
+}
+
+// Avatar UserInput
+func (i *UserInput) Avatar(avatar []byte) *UserInput {
+	out := *i
+	out.data = out.data.set("avatar", avatar)
+	return &out
+}
+
+// AvatarSetNull removes the user's avatar
+func (i *UserInput) AvatarSetNull() *UserInput {
+	out := *i
+	out.data = out.data.set("avatar", nil)
+	return &out
 }
 
 // UserRole enum
@@ -289,17 +987,28 @@ type UserRole string
 // Enums
 // TODO: consider moving
 const (
+	UserRoleUser  UserRole = "USER"
 	UserRoleAdmin UserRole = "ADMIN"
 )
 
+func (r UserRole) enum() string {
+	return string(r)
+}
+
 // Role UserInput
 func (i *UserInput) Role(role UserRole) *UserInput {
-	return i
+	out := *i
+	out.data = out.data.set("role", role)
+	return &out
 }
 
 // CreatePosts creates a new post input
 func (i *UserInput) CreatePosts(posts ...*PostInput) *UserInput {
-	return i
+	out := *i
+	for _, post := range posts {
+		out.data = out.data.push("posts", "create", post.input())
+	}
+	return &out
 }
 
 // CreatePosts creates a new post input
@@ -309,16 +1018,30 @@ func (i *UserInput) CreatePosts(posts ...*PostInput) *UserInput {
 
 // ConnectPosts creates a new post input
 func (i *UserInput) ConnectPosts(posts ...*PostConnect) *UserInput {
-	return i
+	out := *i
+	for _, post := range posts {
+		out.data = out.data.push("posts", "connect", post.where)
+	}
+	return &out
 }
 
 // UserConnect struct
 type UserConnect struct {
+	where object
+}
+
+// ID connection
+func (u *UserConnect) ID(id string) *UserConnect {
+	out := *u
+	out.where = out.where.set("id", id)
+	return &out
 }
 
 // Email connection
 func (u *UserConnect) Email(email string) *UserConnect {
-	return u
+	out := *u
+	out.where = out.where.set("email", email)
+	return &out
 }
 
 // UserCondition interface
@@ -328,172 +1051,787 @@ type UserCondition interface {
 
 // contains user condition state
 type userCondition struct {
+	conditions
+}
+
+func mergeUserConditions(conds []UserCondition) *userCondition {
+	var c userCondition
+	for _, cond := range conds {
+		c.merge(&cond.condition().conditions)
+	}
+	return &c
 }
 
 // UserWhere struct
 type UserWhere struct {
-	c *userCondition
+	f object
+	// cond are the If conditions, which don't identify the user
+	cond object
 }
 
 var _ UserCondition = (*UserWhere)(nil)
 
 // ID condition
 func (w *UserWhere) ID(id string) *UserWhere {
-	return w
+	out := *w
+	out.f = out.f.set("id", id)
+	return &out
 }
 
 // Email condition
 func (w *UserWhere) Email(email string) *UserWhere {
-	return w
+	out := *w
+	out.f = out.f.set("email", email)
+	return &out
 }
 
 // NameContains where name contains substr
 func (w *UserWhere) NameContains(substr string) *UserWhere {
-	return w
+	out := *w
+	out.f = out.f.set("name_contains", substr)
+	return &out
 }
 
 // NameIn where the name is in
 func (w *UserWhere) NameIn(names ...string) *UserWhere {
-	return w
+	out := *w
+	out.f = out.f.set("name_in", names)
+	return &out
 }
 
-func (w *UserWhere) condition() *userCondition {
-	return w.c
+// NameIsNull where the name is null
+func (w *UserWhere) NameIsNull() *UserWhere {
+	out := *w
+	out.f = out.f.set("name", nil)
+	return &out
 }
 
-// UserOrder struct
-type UserOrder struct {
-	c *userCondition
+// NameIsNotNull where the name is not null
+func (w *UserWhere) NameIsNotNull() *UserWhere {
+	out := *w
+	out.f = out.f.set("name_not", nil)
+	return &out
 }
 
-var _ UserCondition = (*UserOrder)(nil)
+// BalanceGt where the balance is greater than balance
+func (w *UserWhere) BalanceGt(balance Decimal) *UserWhere {
+	out := *w
+	out.f = out.f.set("balance_gt", balance)
+	return &out
+}
 
-// Name condition
-func (w *UserOrder) Name(order OrderBy) *UserOrder {
-	return w
+// BalanceLt where the balance is less than balance
+func (w *UserWhere) BalanceLt(balance Decimal) *UserWhere {
+	out := *w
+	out.f = out.f.set("balance_lt", balance)
+	return &out
 }
 
-func (w *UserOrder) condition() *userCondition {
-	return w.c
+// Avatar where the avatar is exactly avatar
+func (w *UserWhere) Avatar(avatar []byte) *UserWhere {
+	out := *w
+	out.f = out.f.set("avatar", avatar)
+	return &out
 }
 
-// UserFirst struct
-type UserFirst struct {
-	c *userCondition
+// AvatarIsNull where the user has no avatar
+func (w *UserWhere) AvatarIsNull() *UserWhere {
+	out := *w
+	out.f = out.f.set("avatar", nil)
+	return &out
 }
 
-func (w *UserFirst) condition() *userCondition {
-	return w.c
+func (w *UserWhere) condition() *userCondition {
+	return &userCondition{conditions{where: w.filter()}}
 }
 
-// UserSelect struct
-type UserSelect struct {
-	c     *userCondition
-	id    bool
-	name  bool
-	email bool
+func (w *UserWhere) filter() object {
+	if w == nil {
+		return nil
+	}
+	return and(w.f, w.cond)
+}
+
+// If requires the conditions to still hold when updating the user. The
+// update fails with ErrConflict when they don't. The updated user is read
+// back in the same transaction when the transport supports them, otherwise
+// a concurrent write in between can make the update return ErrNotFound or
+// another write's user.
+func (w *UserWhere) If(conditions ...*UserWhere) *UserWhere {
+	out := *w
+	out.cond = and(out.cond, andUserWhere(conditions))
+	return &out
+}
+
+// mergeUserWhere merges unique conditions into a single where, leaving out
+// the If conditions
+func mergeUserWhere(where []*UserWhere) (unique object, cond object) {
+	var filters, conds []object
+	for _, w := range where {
+		if w != nil {
+			filters = append(filters, w.f)
+			conds = append(conds, w.cond)
+		}
+	}
+	return merge(filters...), and(conds...)
 }
 
-func (w *UserSelect) condition() *userCondition {
-	return w.c
+// andUserWhere requires all of the conditions to match
+func andUserWhere(where []*UserWhere) object {
+	filters := make([]object, len(where))
+	for i, w := range where {
+		filters[i] = w.filter()
+	}
+	return and(filters...)
 }
 
-// ID selects the user name
-func (w *UserSelect) ID(id *int) *UserSelect {
-	w.id = true
-	return w
+// UserOrder struct
+type UserOrder struct {
+	o object
 }
 
-// Name selects the user name
-func (w *UserSelect) Name(name *string) *UserSelect {
-	w.name = true
-	return w
+var _ UserCondition = (*UserOrder)(nil)
+
+// ID condition
+func (w *UserOrder) ID(order OrderBy) *UserOrder {
+	out := *w
+	out.o = out.o.set("id", order)
+	return &out
 }
 
-// Email selects the user name
-func (w *UserSelect) Email(email *string) *UserSelect {
-	w.email = true
-	return w
+// Name condition
+func (w *UserOrder) Name(order OrderBy) *UserOrder {
+	out := *w
+	out.o = out.o.set("name", order)
+	return &out
 }
 
-// UserWith struct
-type UserWith struct {
+// NameNullsFirst orders by name, with the users without a name first
+func (w *UserOrder) NameNullsFirst(order OrderBy) *UserOrder {
+	out := *w
+	out.o = out.o.set("name", object{{"sort", order}, {"nulls", nullsFirst}})
+	return &out
+}
+
+// NameNullsLast orders by name, with the users without a name last
+func (w *UserOrder) NameNullsLast(order OrderBy) *UserOrder {
+	out := *w
+	out.o = out.o.set("name", object{{"sort", order}, {"nulls", nullsLast}})
+	return &out
+}
+
+// Balance condition
+func (w *UserOrder) Balance(order OrderBy) *UserOrder {
+	out := *w
+	out.o = out.o.set("balance", order)
+	return &out
+}
+
+// PostsCount orders by the number of posts the user wrote
+func (w *UserOrder) PostsCount(order OrderBy) *UserOrder {
+	out := *w
+	out.o = out.o.set("posts", object{{"count", order}})
+	return &out
+}
+
+// CommentsCount orders by the number of comments the user wrote
+func (w *UserOrder) CommentsCount(order OrderBy) *UserOrder {
+	out := *w
+	out.o = out.o.set("comments", object{{"count", order}})
+	return &out
+}
+
+func (w *UserOrder) condition() *userCondition {
+	return &userCondition{conditions{orderBy: w.o}}
+}
+
+// UserDistinct condition
+type UserDistinct []UserField
+
+var _ UserCondition = (*UserDistinct)(nil)
+
+func (w *UserDistinct) condition() *userCondition {
+	fields := make([]enum, len(*w))
+	for i, field := range *w {
+		fields[i] = field
+	}
+	return &userCondition{conditions{distinct: fields}}
+}
+
+// UserFirst condition
+type UserFirst int
+
+func (w *UserFirst) condition() *userCondition {
+	first := int(*w)
+	return &userCondition{conditions{first: &first}}
+}
+
+// UserAfter condition
+type UserAfter string
+
+func (w *UserAfter) condition() *userCondition {
+	after := string(*w)
+	return &userCondition{conditions{after: &after}}
+}
+
+// UserBefore condition
+type UserBefore string
+
+func (w *UserBefore) condition() *userCondition {
+	before := string(*w)
+	return &userCondition{conditions{before: &before}}
+}
+
+// UserSkip condition
+type UserSkip int
+
+func (w *UserSkip) condition() *userCondition {
+	skip := int(*w)
+	return &userCondition{conditions{skip: &skip}}
+}
+
+// UserLast condition
+type UserLast int
+
+func (w *UserLast) condition() *userCondition {
+	last := int(*w)
+	return &userCondition{conditions{last: &last}}
+}
+
+// UserWith struct
+type UserWith struct {
+	with   []*include
+	counts []*include
+}
+
+var _ UserCondition = (*UserWith)(nil)
+
+// Posts includes the user's posts
+func (u *UserWith) Posts(conditions ...PostCondition) *UserWith {
+	out := *u
+	c := mergePostConditions(conditions)
+	out.with = includes(out.with, &include{relation: "posts", args: c.args(c.deleted.filter(postRules.deletedAt)), fields: postFields, nested: &c.conditions})
+	return &out
+}
+
+// Comments includes the comments written by the user
+func (u *UserWith) Comments(conditions ...CommentCondition) *UserWith {
+	out := *u
+	c := mergeCommentConditions(conditions)
+	out.with = includes(out.with, &include{relation: "comments", args: c.args(nil), fields: commentFields, nested: &c.conditions})
+	return &out
+}
+
+// PostsCount filters the posts the user wrote when they're counted by
+// Select into a PostsCount field. Without it every related record is counted.
+func (u *UserWith) PostsCount(where ...*PostWhere) *UserWith {
+	out := *u
+	out.counts = includes(out.counts, &include{relation: "posts", args: whereArgs(postDeleted(where).filter(postRules.deletedAt), andPostWhere(where))})
+	return &out
+}
+
+// CommentsCount filters the comments the user wrote when they're counted by
+// Select into a CommentsCount field. Without it every related record is counted.
+func (u *UserWith) CommentsCount(where ...*CommentWhere) *UserWith {
+	out := *u
+	out.counts = includes(out.counts, &include{relation: "comments", args: whereArgs(nil, andCommentWhere(where))})
+	return &out
 }
 
 func (u *UserWith) condition() *userCondition {
-	return &userCondition{}
+	return &userCondition{conditions{with: u.with, counts: u.counts}}
 }
 
 // UserAs is a chaining element for user
 type UserAs struct {
+	// Post through the user's posts
 	Post *PostModel
+	// Comment through the comments written by the user
+	Comment *CommentModel
 }
 
-// Post finds a post as the user
-// func (u *UserAs) Post(conditions ...PostCondition) (post *Post, err error) {
-// 	return post, err
-// }
+// Post struct
+type Post struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Title       string    `json:"title"`
+	Published   bool      `json:"published"`
+	Views       int       `json:"views"`
+	Impressions BigInt    `json:"impressions"`
+	Metadata    JSON      `json:"metadata"`  // nil when null
+	Version     int       `json:"version"`   // Version is bumped by every update
+	DeletedAt   NullTime  `json:"deletedAt"` // DeletedAt is set when the post is deleted
+
+	// Relations are only filled in when included with With
+	Relations PostRelations `json:"-"`
+
+	// client that returned the post, used to lazily load relations
+	client *Client
+}
+
+// PostRelations included with post.WithAuthor and post.WithComments. A nil
+// relation wasn't included, while an included relation without any records
+// is an empty slice. Posts may not have an author, so AuthorLoaded tells
+// whether the author was included.
+type PostRelations struct {
+	Author       *User      `json:"author"`
+	AuthorLoaded bool       `json:"-"`
+	Comments     []*Comment `json:"comments"`
+}
+
+// UnmarshalJSON decodes a post along with its included relations
+func (p *Post) UnmarshalJSON(data []byte) error {
+	type post Post
+	if err := json.Unmarshal(data, (*post)(p)); err != nil {
+		return err
+	}
+	var author struct {
+		Author json.RawMessage `json:"author"`
+	}
+	if err := json.Unmarshal(data, &author); err != nil {
+		return err
+	}
+	p.Relations.AuthorLoaded = author.Author != nil
+	return json.Unmarshal(data, &p.Relations)
+}
 
-// // Posts finds many posts as the user
-// func (u *UserAs) Posts(conditions ...PostCondition) (posts []*Post, err error) {
-// 	return posts, err
-// }
+func (p *Post) attach(c *Client) {
+	p.client = c
+	if p.Relations.Author != nil {
+		p.Relations.Author.attach(c)
+	}
+	for _, comment := range p.Relations.Comments {
+		comment.attach(c)
+	}
+}
 
-// // AsPost fn
-// func (u *UserAs) AsPost(conditions ...PostCondition) *PostAs {
-// 	return &PostAs{}
-// }
+// Author loads the post's author, ErrNotFound if the post has no author.
+// An author included with post.WithAuthor is returned without a query.
+func (p *Post) Author(ctx context.Context) (*User, error) {
+	if p.Relations.AuthorLoaded {
+		if p.Relations.Author == nil {
+			return nil, ErrNotFound
+		}
+		return p.Relations.Author, nil
+	}
+	if p.client == nil {
+		return nil, ErrDetached
+	}
+	one := 1
+	model := &UserModel{client: p.client, scope: object{{"posts_some", object{{"id", p.ID}}}}}
+	var users []*User
+	if err := p.client.load(ctx, model.findMany(&userCondition{conditions{first: &one}}), &users); err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, ErrNotFound
+	}
+	return users[0], nil
+}
 
-// Post struct
-type Post struct {
-	ID        string
-	Title     string
-	Published bool
+// Comments loads the post's comments. Comments included with
+// post.WithComments are returned without a query when there are no conditions.
+func (p *Post) Comments(ctx context.Context, conditions ...CommentCondition) ([]*Comment, error) {
+	if p.Relations.Comments != nil && len(conditions) == 0 {
+		return p.Relations.Comments, nil
+	}
+	if p.client == nil {
+		return nil, ErrDetached
+	}
+	model := &CommentModel{client: p.client, scope: object{{"post", object{{"id", p.ID}}}}}
+	var comments []*Comment
+	if err := p.client.load(ctx, model.findMany(mergeCommentConditions(conditions)), &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
 }
 
+// postFields are selected when returning posts
+const postFields = "id createdAt updatedAt title published views impressions metadata version deletedAt"
+
 // PostModel struct
 type PostModel struct {
-	db DB
+	client *Client
+	// scope is set when the model is reached through As
+	scope object
+}
+
+// live scopes reads to the posts that aren't deleted, unless the conditions
+// ask for the deleted posts with WithDeleted or OnlyDeleted
+func (p *PostModel) live(deleted deletedScope) object {
+	return and(p.scope, deleted.filter(postRules.deletedAt))
 }
 
 // Find a post by a condition
 func (p *PostModel) Find(conditions ...PostCondition) (post *Post, err error) {
-	return post, err
+	c := mergePostConditions(conditions)
+	if c.first == nil && c.last == nil {
+		one := 1
+		c.first = &one
+	}
+	var posts []*Post
+	if err := p.client.send(p.findMany(c), &posts); err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, ErrNotFound
+	}
+	return posts[0], nil
+}
+
+// Select the posts matching the conditions into v, a pointer to a struct or
+// a slice of structs whose fields are named after the post's fields and
+// relations. A CommentsCount field holds the number of
+// comments.
+func (p *PostModel) Select(v interface{}, conditions ...PostCondition) error {
+	c := mergePostConditions(conditions)
+	return p.client.selectInto(postSchema, p.live(c.deleted), &c.conditions, v)
 }
 
 // FindMany posts by a condition
 func (p *PostModel) FindMany(conditions ...PostCondition) (posts []*Post, err error) {
-	return posts, err
+	if err := p.client.send(p.findMany(mergePostConditions(conditions)), &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (p *PostModel) findMany(c *postCondition) *operation {
+	return &operation{
+		name:      "findManyPost",
+		args:      c.args(p.live(c.deleted)),
+		selection: postFields,
+		related:   c.related(),
+	}
+}
+
+// FindForUpdate finds the posts matching the conditions and locks them
+// against updates until the transaction ends. It fails with
+// ErrNoTransaction outside of Transaction.
+func (p *PostModel) FindForUpdate(conditions ...PostCondition) ([]*Post, error) {
+	return (&PostLocking{model: p}).FindForUpdate(conditions...)
+}
+
+// FindForShare is like FindForUpdate, but other transactions can still
+// lock the posts for sharing
+func (p *PostModel) FindForShare(conditions ...PostCondition) ([]*Post, error) {
+	return (&PostLocking{model: p}).FindForShare(conditions...)
+}
+
+// SkipLocked leaves out the posts that are already locked, so concurrent
+// workers each claim different posts
+func (p *PostModel) SkipLocked() *PostLocking {
+	return &PostLocking{model: p, wait: lockSkipLocked}
+}
+
+// NoWait fails instead of waiting when a post is already locked
+func (p *PostModel) NoWait() *PostLocking {
+	return &PostLocking{model: p, wait: lockNoWait}
+}
+
+// PostLocking is a chaining element for locking posts
+type PostLocking struct {
+	model *PostModel
+	wait  lockWait
+}
+
+// FindForUpdate finds the posts matching the conditions and locks them
+// against updates until the transaction ends
+func (l *PostLocking) FindForUpdate(conditions ...PostCondition) ([]*Post, error) {
+	return l.find(lockUpdate, conditions)
+}
+
+// FindForShare finds the posts matching the conditions and locks them
+// against updates, but not other shared locks, until the transaction ends
+func (l *PostLocking) FindForShare(conditions ...PostCondition) ([]*Post, error) {
+	return l.find(lockShare, conditions)
+}
+
+func (l *PostLocking) find(mode lockMode, conditions []PostCondition) (posts []*Post, err error) {
+	op, err := l.model.client.lock(l.model.findMany(mergePostConditions(conditions)), mode, l.wait)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.model.client.send(op, &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// FindEach calls fn with each post matching the conditions as it's
+// decoded, without holding the whole result in memory. It stops at the first
+// error returned by fn and returns it.
+func (p *PostModel) FindEach(ctx context.Context, fn func(*Post) error, conditions ...PostCondition) error {
+	op := p.findMany(mergePostConditions(conditions))
+	return p.client.stream(ctx, op, func(record json.RawMessage) error {
+		post := new(Post)
+		if err := json.Unmarshal(record, post); err != nil {
+			return err
+		}
+		post.attach(p.client)
+		return fn(post)
+	})
+}
+
+// Stream the posts matching the conditions over a channel. The channel
+// is unbuffered, so the posts are only decoded as fast as they're
+// received. Cancel the context to stop early. The error channel receives the
+// result once the posts channel is closed.
+func (p *PostModel) Stream(ctx context.Context, conditions ...PostCondition) (<-chan *Post, <-chan error) {
+	posts := make(chan *Post)
+	errc := make(chan error, 1)
+	go func() {
+		err := p.FindEach(ctx, func(post *Post) error {
+			select {
+			case posts <- post:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}, conditions...)
+		close(posts)
+		errc <- err
+		close(errc)
+	}()
+	return posts, errc
+}
+
+// Iterate over the posts matching the conditions. Posts are fetched
+// in batches using the After cursor, keeping the order of the conditions.
+func (p *PostModel) Iterate(ctx context.Context, conditions ...PostCondition) *PostIterator {
+	c := mergePostConditions(conditions)
+	return &PostIterator{
+		model: p,
+		cond:  c.conditions,
+		pager: newPager(ctx, &c.conditions),
+	}
+}
+
+// PostIterator iterates over posts in batches
+type PostIterator struct {
+	model *PostModel
+	cond  conditions
+	pager pager
+	batch []*Post
+	value *Post
+}
+
+// BatchSize sets how many posts are fetched per query
+func (it *PostIterator) BatchSize(size int) *PostIterator {
+	it.pager.size = size
+	return it
+}
+
+// Next advances to the next post, fetching another batch when needed.
+// It returns false when there are no more posts, the context is done or
+// a query fails.
+func (it *PostIterator) Next() bool {
+	if !it.pager.check() {
+		return false
+	}
+	if len(it.batch) == 0 {
+		c, ok := it.pager.next(it.cond)
+		if !ok {
+			return false
+		}
+		var posts []*Post
+		err := it.model.client.sendContext(it.pager.ctx, it.model.findMany(&postCondition{*c}), &posts)
+		cursor := ""
+		if len(posts) > 0 {
+			cursor = posts[len(posts)-1].ID
+		}
+		it.pager.advance(len(posts), cursor, err)
+		if err != nil || len(posts) == 0 {
+			return false
+		}
+		it.batch = posts
+	}
+	it.value, it.batch = it.batch[0], it.batch[1:]
+	return true
+}
+
+// Value returns the current post
+func (it *PostIterator) Value() *Post {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *PostIterator) Err() error {
+	return it.pager.err
+}
+
+// Count the posts matching the conditions
+func (p *PostModel) Count(where ...*PostWhere) (int64, error) {
+	var result BatchPayload
+	op := &operation{
+		name:      "aggregatePost",
+		args:      whereArgs(p.live(postDeleted(where)), andPostWhere(where)),
+		selection: "count",
+	}
+	if err := p.client.send(op, &result); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
 }
 
-// Create a post
+// Create a post. The post's create hooks run around it.
 func (p *PostModel) Create(post *PostInput) (*Post, error) {
-	return nil, nil
+	if p.scope != nil {
+		return nil, ErrScoped
+	}
+	if err := postRules.validate(post.input(), true); err != nil {
+		return nil, err
+	}
+	// the generated fields are filled in first, so the hooks see them
+	post = &PostInput{data: postRules.stamp(post.input(), true, p.client.now())}
+	if err := p.client.policies.allow(p.client.Context(), "Post", post); err != nil {
+		return nil, err
+	}
+	result, err := p.client.hooked("Post", hookCreate, post, func(c *Client) (interface{}, error) {
+		return c.Post.create(post)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Post), nil
+}
+
+func (p *PostModel) create(post *PostInput) (*Post, error) {
+	op := &operation{
+		mutation:  true,
+		name:      "createOnePost",
+		args:      object{{"data", post.input()}},
+		selection: postFields,
+	}
+	var result *Post
+	if err := p.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateMany creates posts in bulk and reports how many were created
+func (p *PostModel) CreateMany(ctx context.Context, posts []*PostInput, opts *CreateManyOptions) (*BatchPayload, error) {
+	if p.scope != nil {
+		return nil, ErrScoped
+	}
+	rows := make([]object, len(posts))
+	for i, post := range posts {
+		rows[i] = post.input()
+	}
+	if err := postRules.validateRows(rows); err != nil {
+		return nil, err
+	}
+	rows = postRules.stampRows(rows, p.client.now())
+	for _, row := range rows {
+		if err := p.client.policies.allow(ctx, "Post", &PostInput{data: row}); err != nil {
+			return nil, err
+		}
+	}
+	return p.client.createMany(ctx, "Post", postRules.unique, rows, opts)
 }
 
-// Update a post
+// Update a post. The post's update hooks run around it.
 func (p *PostModel) Update(post *PostInput, where ...*PostWhere) (*Post, error) {
-	return nil, nil
+	if p.scope != nil {
+		return nil, ErrScoped
+	}
+	if err := postRules.validate(post.input(), false); err != nil {
+		return nil, err
+	}
+	result, err := p.client.hooked("Post", hookUpdate, post, func(c *Client) (interface{}, error) {
+		return c.Post.update(post, where)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Post), nil
+}
+
+func (p *PostModel) update(post *PostInput, where []*PostWhere) (*Post, error) {
+	data := postRules.stamp(postRules.versioned(post.input()), false, p.client.now())
+	unique, cond := mergePostWhere(where)
+	if len(cond) > 0 {
+		var result *Post
+		err := p.client.atomically(func(c *Client) (err error) {
+			if err := c.updateIf("Post", data, unique, cond); err != nil {
+				return err
+			}
+			result, err = c.Post.Find(&PostWhere{f: merge(unique, pick(data, postRules.unique))})
+			return err
+		})
+		return result, err
+	}
+	op := &operation{
+		mutation:  true,
+		name:      "updateOnePost",
+		args:      object{{"data", data}, {"where", unique}},
+		selection: postFields,
+	}
+	var result *Post
+	if err := p.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-// UpdateMany posts
-func (p *PostModel) UpdateMany(post *PostInput, where ...*PostWhere) ([]*Post, error) {
-	return nil, nil
+// UpdateMany updates every post matching the conditions and reports how
+// many were updated
+func (p *PostModel) UpdateMany(post *PostInput, where ...*PostWhere) (*BatchPayload, error) {
+	if err := postRules.validate(post.input(), false); err != nil {
+		return nil, err
+	}
+	op := &operation{
+		mutation:  true,
+		name:      "updateManyPost",
+		args:      whereArgs(p.live(postDeleted(where)), andPostWhere(where)).set("data", postRules.stamp(postRules.versioned(post.input()), false, p.client.now())),
+		selection: "count",
+	}
+	var result BatchPayload
+	if err := p.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
-// Delete a post
+// Delete a post by setting its deletedAt, after which it's left out of
+// reads. The post's delete hooks run around it.
 func (p *PostModel) Delete(where *PostWhere) (*Post, error) {
-	return nil, nil
+	if p.scope != nil {
+		return nil, ErrScoped
+	}
+	result, err := p.client.hooked("Post", hookDelete, where, func(c *Client) (interface{}, error) {
+		return c.Post.delete(where)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Post), nil
 }
 
-// DeleteMany a post
-func (p *PostModel) DeleteMany(where *PostWhere) (*Post, error) {
-	return nil, nil
+func (p *PostModel) delete(where *PostWhere) (*Post, error) {
+	unique, cond := mergePostWhere([]*PostWhere{where})
+	if err := p.client.softDelete(postRules, postRules.deletedAt, unique, cond); err != nil {
+		return nil, err
+	}
+	return p.Find(&PostWhere{f: unique, deleted: onlyDeleted})
+}
+
+// DeleteMany sets the deletedAt of every post matching the condition and
+// reports how many were deleted. Posts that were already deleted aren't
+// counted.
+func (p *PostModel) DeleteMany(where *PostWhere) (*BatchPayload, error) {
+	return p.client.softDeleteMany(postRules, postRules.deletedAt, and(p.scope, where.filter()))
 }
 
 // As post, find a nested entity
 func (p *PostModel) As(where *PostWhere) *PostAs {
-	return &PostAs{}
+	scope := and(p.live(postDeleted([]*PostWhere{where})), where.filter())
+	return &PostAs{
+		User:    &UserModel{client: p.client, scope: object{{"posts_some", scope}}},
+		Comment: &CommentModel{client: p.client, scope: object{{"post", scope}}},
+	}
 }
 
 //
@@ -502,30 +1840,145 @@ func (p *PostModel) As(where *PostWhere) *PostAs {
 
 // PostInput struct
 type PostInput struct {
+	data object
+}
+
+func (i *PostInput) input() object {
+	if i == nil {
+		return object{}
+	}
+	return i.data
+}
+
+// ID sets the id of a new post, which is generated when it isn't set
+func (i *PostInput) ID(id string) *PostInput {
+	out := *i
+	out.data = out.data.set("id", id)
+	return &out
 }
 
 // Title PostInput
 func (i *PostInput) Title(name string) *PostInput {
-	return i
+	out := *i
+	out.data = out.data.set("title", name)
+	return &out
 }
 
 // Published PostInput
 func (i *PostInput) Published(published bool) *PostInput {
-	return i
+	out := *i
+	out.data = out.data.set("published", published)
+	return &out
+}
+
+// Views PostInput
+func (i *PostInput) Views(views int) *PostInput {
+	out := *i
+	out.data = out.data.set("views", views)
+	return &out
+}
+
+// ViewsIncrement atomically adds n to the views
+func (i *PostInput) ViewsIncrement(n int) *PostInput {
+	out := *i
+	out.data = out.data.set("views", object{{"increment", n}})
+	return &out
+}
+
+// ViewsDecrement atomically subtracts n from the views
+func (i *PostInput) ViewsDecrement(n int) *PostInput {
+	out := *i
+	out.data = out.data.set("views", object{{"decrement", n}})
+	return &out
+}
+
+// ViewsMultiply atomically multiplies the views by n
+func (i *PostInput) ViewsMultiply(n int) *PostInput {
+	out := *i
+	out.data = out.data.set("views", object{{"multiply", n}})
+	return &out
+}
+
+// ViewsDivide atomically divides the views by n
+func (i *PostInput) ViewsDivide(n int) *PostInput {
+	out := *i
+	out.data = out.data.set("views", object{{"divide", n}})
+	return &out
+}
+
+// Impressions PostInput
+func (i *PostInput) Impressions(impressions BigInt) *PostInput {
+	out := *i
+	out.data = out.data.set("impressions", impressions)
+	return &out
+}
+
+// ImpressionsIncrement atomically adds n to the impressions
+func (i *PostInput) ImpressionsIncrement(n BigInt) *PostInput {
+	out := *i
+	out.data = out.data.set("impressions", object{{"increment", n}})
+	return &out
+}
+
+// ImpressionsDecrement atomically subtracts n from the impressions
+func (i *PostInput) ImpressionsDecrement(n BigInt) *PostInput {
+	out := *i
+	out.data = out.data.set("impressions", object{{"decrement", n}})
+	return &out
+}
+
+// ImpressionsMultiply atomically multiplies the impressions by n
+func (i *PostInput) ImpressionsMultiply(n BigInt) *PostInput {
+	out := *i
+	out.data = out.data.set("impressions", object{{"multiply", n}})
+	return &out
+}
+
+// ImpressionsDivide atomically divides the impressions by n
+func (i *PostInput) ImpressionsDivide(n BigInt) *PostInput {
+	out := *i
+	out.data = out.data.set("impressions", object{{"divide", n}})
+	return &out
+}
+
+// Metadata PostInput
+func (i *PostInput) Metadata(metadata JSON) *PostInput {
+	out := *i
+	out.data = out.data.set("metadata", metadata)
+	return &out
+}
+
+// MetadataSetNull removes the post's metadata
+func (i *PostInput) MetadataSetNull() *PostInput {
+	out := *i
+	out.data = out.data.set("metadata", nil)
+	return &out
 }
 
 // ConnectAuthor connects the author to the postInput
 func (i *PostInput) ConnectAuthor(user *UserConnect) *PostInput {
-	return i
+	out := *i
+	out.data = out.data.set("author", object{{"connect", user.where}})
+	return &out
+}
+
+// DisconnectAuthor removes the post's author
+func (i *PostInput) DisconnectAuthor() *PostInput {
+	out := *i
+	out.data = out.data.set("author", object{{"disconnect", true}})
+	return &out
 }
 
 // PostConnect struct
 type PostConnect struct {
+	where object
 }
 
 // ID connection
 func (p *PostConnect) ID(id string) *PostConnect {
-	return p
+	out := *p
+	out.where = out.where.set("id", id)
+	return &out
 }
 
 // PostCondition interface
@@ -535,200 +1988,891 @@ type PostCondition interface {
 
 // contains post condition state
 type postCondition struct {
+	conditions
+}
+
+func mergePostConditions(conds []PostCondition) *postCondition {
+	var c postCondition
+	for _, cond := range conds {
+		c.merge(&cond.condition().conditions)
+	}
+	return &c
 }
 
 // PostWhere struct
 type PostWhere struct {
-	p *postCondition
+	f object
+	// cond are the If conditions, which don't identify the post
+	cond object
+	// deleted is whether deleted posts match
+	deleted deletedScope
 }
 
 var _ PostCondition = (*PostWhere)(nil)
 
 // Or condition
 func (w *PostWhere) Or(conditions ...*PostWhere) *PostWhere {
-	return &PostWhere{}
+	out := *w
+	filters := make([]object, len(conditions))
+	for i, c := range conditions {
+		filters[i] = c.filter()
+	}
+	out.f = out.f.set("OR", filters)
+	return &out
 }
 
 // ID condition
 func (w *PostWhere) ID(id string) *PostWhere {
-	return w
+	out := *w
+	out.f = out.f.set("id", id)
+	return &out
 }
 
 // IDIn condition
 func (w *PostWhere) IDIn(ids ...string) *PostWhere {
-	return w
+	out := *w
+	out.f = out.f.set("id_in", ids)
+	return &out
 }
 
 // Title condition
 func (w *PostWhere) Title(title string) *PostWhere {
-	return w
+	out := *w
+	out.f = out.f.set("title", title)
+	return &out
+}
+
+// Published condition
+func (w *PostWhere) Published(published bool) *PostWhere {
+	out := *w
+	out.f = out.f.set("published", published)
+	return &out
 }
 
 // TitleContains condition
 func (w *PostWhere) TitleContains(subtitle string) *PostWhere {
-	return w
+	out := *w
+	out.f = out.f.set("title_contains", subtitle)
+	return &out
 }
 
 // CreatedAtGt condition
 func (w *PostWhere) CreatedAtGt(createdAt time.Time) *PostWhere {
-	return w
+	out := *w
+	out.f = out.f.set("createdAt_gt", createdAt)
+	return &out
+}
+
+// Version condition
+func (w *PostWhere) Version(version int) *PostWhere {
+	out := *w
+	out.f = out.f.set("version", version)
+	return &out
+}
+
+// IfVersion requires the post to still be at version when updating it, so
+// concurrent edits fail with ErrConflict instead of overwriting each other
+func (w *PostWhere) IfVersion(version int) *PostWhere {
+	return w.If((&PostWhere{}).Version(version))
+}
+
+// ImpressionsGt condition
+func (w *PostWhere) ImpressionsGt(impressions BigInt) *PostWhere {
+	out := *w
+	out.f = out.f.set("impressions_gt", impressions)
+	return &out
+}
+
+// ImpressionsLt condition
+func (w *PostWhere) ImpressionsLt(impressions BigInt) *PostWhere {
+	out := *w
+	out.f = out.f.set("impressions_lt", impressions)
+	return &out
+}
+
+// Metadata where the metadata equals metadata
+func (w *PostWhere) Metadata(metadata JSON) *PostWhere {
+	out := *w
+	out.f = out.f.set("metadata", metadata)
+	return &out
+}
+
+// MetadataIsNull where the post has no metadata
+func (w *PostWhere) MetadataIsNull() *PostWhere {
+	out := *w
+	out.f = out.f.set("metadata", nil)
+	return &out
+}
+
+// MetadataPathEquals where the value at path within the metadata equals
+// value. Path filters are ANDed, so several paths can be filtered at once.
+func (w *PostWhere) MetadataPathEquals(path []string, value JSON) *PostWhere {
+	out := *w
+	out.f = out.f.also(object{{"metadata", object{{"path", path}, {"equals", value}}}})
+	return &out
+}
+
+// MetadataPathContains where the string at path within the metadata
+// contains substr
+func (w *PostWhere) MetadataPathContains(path []string, substr string) *PostWhere {
+	out := *w
+	out.f = out.f.also(object{{"metadata", object{{"path", path}, {"string_contains", substr}}}})
+	return &out
+}
+
+// AuthorIsNull where the post has no author
+func (w *PostWhere) AuthorIsNull() *PostWhere {
+	out := *w
+	out.f = out.f.set("author", nil)
+	return &out
+}
+
+// AuthorIsNotNull where the post has an author
+func (w *PostWhere) AuthorIsNotNull() *PostWhere {
+	out := *w
+	out.f = out.f.set("author_not", nil)
+	return &out
+}
+
+// AuthorID where the post was written by the user with the id
+func (w *PostWhere) AuthorID(id string) *PostWhere {
+	out := *w
+	out.f = out.f.set("author", object{{"id", id}})
+	return &out
 }
 
 func (w *PostWhere) condition() *postCondition {
-	return w.p
+	return &postCondition{conditions{where: w.filter(), deleted: w.deleted}}
 }
 
-// PostFirst struct
-type PostFirst struct {
-	c *postCondition
+// WithDeleted matches the deleted posts along with the others
+func (w *PostWhere) WithDeleted() *PostWhere {
+	out := *w
+	out.deleted = withDeleted
+	return &out
 }
 
-func (w *PostFirst) condition() *postCondition {
-	return w.c
+// OnlyDeleted only matches the deleted posts
+func (w *PostWhere) OnlyDeleted() *PostWhere {
+	out := *w
+	out.deleted = onlyDeleted
+	return &out
 }
 
-// PostAfter struct
-type PostAfter struct {
-	c *postCondition
+// postDeleted is whether the conditions match deleted posts, the last
+// condition that says so wins
+func postDeleted(where []*PostWhere) (deleted deletedScope) {
+	for _, w := range where {
+		if w != nil && w.deleted != excludeDeleted {
+			deleted = w.deleted
+		}
+	}
+	return deleted
 }
 
-func (w *PostAfter) condition() *postCondition {
-	return w.c
+func (w *PostWhere) filter() object {
+	if w == nil {
+		return nil
+	}
+	return and(w.f, w.cond)
+}
+
+// If requires the conditions to still hold when updating the post. The
+// update fails with ErrConflict when they don't. The updated post is read
+// back in the same transaction when the transport supports them, otherwise
+// a concurrent write in between can make the update return ErrNotFound or
+// another write's post.
+func (w *PostWhere) If(conditions ...*PostWhere) *PostWhere {
+	out := *w
+	out.cond = and(out.cond, andPostWhere(conditions))
+	return &out
+}
+
+// mergePostWhere merges unique conditions into a single where, leaving out
+// the If conditions
+func mergePostWhere(where []*PostWhere) (unique object, cond object) {
+	var filters, conds []object
+	for _, w := range where {
+		if w != nil {
+			filters = append(filters, w.f)
+			conds = append(conds, w.cond)
+		}
+	}
+	return merge(filters...), and(conds...)
 }
 
-// PostBefore struct
-type PostBefore struct {
-	c *postCondition
+// andPostWhere requires all of the conditions to match
+func andPostWhere(where []*PostWhere) object {
+	filters := make([]object, len(where))
+	for i, w := range where {
+		filters[i] = w.filter()
+	}
+	return and(filters...)
 }
 
-func (w *PostBefore) condition() *postCondition {
-	return w.c
+// PostOrder struct
+type PostOrder struct {
+	o object
 }
 
-// PostSkip struct
-type PostSkip struct {
-	c *postCondition
+var _ PostCondition = (*PostOrder)(nil)
+
+// ID condition
+func (w *PostOrder) ID(order OrderBy) *PostOrder {
+	out := *w
+	out.o = out.o.set("id", order)
+	return &out
 }
 
-func (w *PostSkip) condition() *postCondition {
-	return w.c
+// CreatedAt condition
+func (w *PostOrder) CreatedAt(order OrderBy) *PostOrder {
+	out := *w
+	out.o = out.o.set("createdAt", order)
+	return &out
 }
 
-// PostLast struct
-type PostLast struct {
-	c *postCondition
+// Title condition
+func (w *PostOrder) Title(order OrderBy) *PostOrder {
+	out := *w
+	out.o = out.o.set("title", order)
+	return &out
 }
 
-func (w *PostLast) condition() *postCondition {
-	return w.c
+// Impressions condition
+func (w *PostOrder) Impressions(order OrderBy) *PostOrder {
+	out := *w
+	out.o = out.o.set("impressions", order)
+	return &out
 }
 
-// PostSelect struct
-type PostSelect struct {
-	c *postCondition
+// CommentsCount orders by the number of comments on the post
+func (w *PostOrder) CommentsCount(order OrderBy) *PostOrder {
+	out := *w
+	out.o = out.o.set("comments", object{{"count", order}})
+	return &out
 }
 
-// ID fn
-func (p *PostSelect) ID(id *int) *PostSelect {
-	return p
+func (w *PostOrder) condition() *postCondition {
+	return &postCondition{conditions{orderBy: w.o}}
 }
 
-// Title fn
-func (p *PostSelect) Title(title *string) *PostSelect {
-	return p
+// PostDistinct condition
+type PostDistinct []PostField
+
+var _ PostCondition = (*PostDistinct)(nil)
+
+func (w *PostDistinct) condition() *postCondition {
+	fields := make([]enum, len(*w))
+	for i, field := range *w {
+		fields[i] = field
+	}
+	return &postCondition{conditions{distinct: fields}}
+}
+
+// PostFirst condition
+type PostFirst int
+
+func (w *PostFirst) condition() *postCondition {
+	first := int(*w)
+	return &postCondition{conditions{first: &first}}
+}
+
+// PostAfter condition
+type PostAfter string
+
+func (w *PostAfter) condition() *postCondition {
+	after := string(*w)
+	return &postCondition{conditions{after: &after}}
+}
+
+// PostBefore condition
+type PostBefore string
+
+func (w *PostBefore) condition() *postCondition {
+	before := string(*w)
+	return &postCondition{conditions{before: &before}}
 }
 
-// CreatedAt fn
-func (p *PostSelect) CreatedAt(createdAt *time.Time) *PostSelect {
-	return p
+// PostSkip condition
+type PostSkip int
+
+func (w *PostSkip) condition() *postCondition {
+	skip := int(*w)
+	return &postCondition{conditions{skip: &skip}}
 }
 
-// condition
-func (p *PostSelect) condition() *postCondition {
-	return p.c
+// PostLast condition
+type PostLast int
+
+func (w *PostLast) condition() *postCondition {
+	last := int(*w)
+	return &postCondition{conditions{last: &last}}
 }
 
 // PostWith struct
 type PostWith struct {
+	with   []*include
+	counts []*include
+}
+
+var _ PostCondition = (*PostWith)(nil)
+
+// Author includes the post's author. To-one relations can't be filtered, but
+// the author's own relations can be included.
+func (p *PostWith) Author(with ...*UserWith) *PostWith {
+	out := *p
+	var c userCondition
+	for _, w := range with {
+		c.merge(&w.condition().conditions)
+	}
+	out.with = includes(out.with, &include{relation: "author", args: nil, fields: userFields, nested: &c.conditions})
+	return &out
+}
+
+// Comments includes the post's comments
+func (p *PostWith) Comments(conditions ...CommentCondition) *PostWith {
+	out := *p
+	c := mergeCommentConditions(conditions)
+	out.with = includes(out.with, &include{relation: "comments", args: c.args(nil), fields: commentFields, nested: &c.conditions})
+	return &out
+}
+
+// CommentsCount filters the comments on the post when they're counted by
+// Select into a CommentsCount field. Without it every related record is counted.
+func (p *PostWith) CommentsCount(where ...*CommentWhere) *PostWith {
+	out := *p
+	out.counts = includes(out.counts, &include{relation: "comments", args: whereArgs(nil, andCommentWhere(where))})
+	return &out
 }
 
 func (p *PostWith) condition() *postCondition {
-	return &postCondition{}
+	return &postCondition{conditions{with: p.with, counts: p.counts}}
 }
 
 // PostAs struct
 type PostAs struct {
+	// User through the post's author
+	User *UserModel
+	// Comment through the post's comments
 	Comment *CommentModel
 }
 
-// // Comment condition
-// func (p *PostAs) Comment(conditions ...CommentCondition) (comment *Comment, err error) {
-// 	return comment, err
-// }
-
-// // Comments condition
-// func (p *PostAs) Comments(conditions ...CommentCondition) (comments []*Comment, err error) {
-// 	return comments, err
-// }
-
 //
 // Comments
 //
 
 // Comment struct
 type Comment struct {
-	ID        int
-	Email     string
-	CreatedAt time.Time
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Text      string    `json:"text"`
+
+	// Relations are only filled in when included with With
+	Relations CommentRelations `json:"-"`
+
+	// client that returned the comment, used to lazily load relations
+	client *Client
+}
+
+// CommentRelations included with comment.WithPost and comment.WithWrittenBy.
+// A nil relation wasn't included.
+type CommentRelations struct {
+	Post      *Post `json:"post"`
+	WrittenBy *User `json:"writtenBy"`
+}
+
+// UnmarshalJSON decodes a comment along with its included relations
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	type comment Comment
+	if err := json.Unmarshal(data, (*comment)(c)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &c.Relations)
+}
+
+func (c *Comment) attach(client *Client) {
+	c.client = client
+	if c.Relations.Post != nil {
+		c.Relations.Post.attach(client)
+	}
+	if c.Relations.WrittenBy != nil {
+		c.Relations.WrittenBy.attach(client)
+	}
 }
 
+// Post loads the post the comment belongs to. A post included with
+// comment.WithPost is returned without a query.
+func (c *Comment) Post(ctx context.Context) (*Post, error) {
+	if c.Relations.Post != nil {
+		return c.Relations.Post, nil
+	}
+	if c.client == nil {
+		return nil, ErrDetached
+	}
+	one := 1
+	model := &PostModel{client: c.client, scope: object{{"comments_some", object{{"id", c.ID}}}}}
+	var posts []*Post
+	if err := c.client.load(ctx, model.findMany(&postCondition{conditions{first: &one}}), &posts); err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, ErrNotFound
+	}
+	return posts[0], nil
+}
+
+// WrittenBy loads the comment's author. An author included with
+// comment.WithWrittenBy is returned without a query.
+func (c *Comment) WrittenBy(ctx context.Context) (*User, error) {
+	if c.Relations.WrittenBy != nil {
+		return c.Relations.WrittenBy, nil
+	}
+	if c.client == nil {
+		return nil, ErrDetached
+	}
+	one := 1
+	model := &UserModel{client: c.client, scope: object{{"comments_some", object{{"id", c.ID}}}}}
+	var users []*User
+	if err := c.client.load(ctx, model.findMany(&userCondition{conditions{first: &one}}), &users); err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, ErrNotFound
+	}
+	return users[0], nil
+}
+
+// commentFields are selected when returning comments
+const commentFields = "id createdAt text"
+
 // CommentModel struct
 type CommentModel struct {
-	db DB
+	client *Client
+	// scope is set when the model is reached through As
+	scope object
 }
 
 // Find a comment by a condition
 func (c *CommentModel) Find(conditions ...CommentCondition) (comment *Comment, err error) {
-	return comment, err
+	cond := mergeCommentConditions(conditions)
+	if cond.first == nil && cond.last == nil {
+		one := 1
+		cond.first = &one
+	}
+	var comments []*Comment
+	if err := c.client.send(c.findMany(cond), &comments); err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, ErrNotFound
+	}
+	return comments[0], nil
+}
+
+// Select the comments matching the conditions into v, a pointer to a struct or
+// a slice of structs whose fields are named after the comment's fields and
+// relations.
+func (c *CommentModel) Select(v interface{}, conditions ...CommentCondition) error {
+	return c.client.selectInto(commentSchema, c.scope, &mergeCommentConditions(conditions).conditions, v)
 }
 
 // FindMany comments by a condition
 func (c *CommentModel) FindMany(conditions ...CommentCondition) (comments []*Comment, err error) {
-	return comments, err
+	if err := c.client.send(c.findMany(mergeCommentConditions(conditions)), &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (c *CommentModel) findMany(cond *commentCondition) *operation {
+	return &operation{
+		name:      "findManyComment",
+		args:      cond.args(c.scope),
+		selection: commentFields,
+		related:   cond.related(),
+	}
+}
+
+// FindForUpdate finds the comments matching the conditions and locks them
+// against updates until the transaction ends. It fails with
+// ErrNoTransaction outside of Transaction.
+func (c *CommentModel) FindForUpdate(conditions ...CommentCondition) ([]*Comment, error) {
+	return (&CommentLocking{model: c}).FindForUpdate(conditions...)
+}
+
+// FindForShare is like FindForUpdate, but other transactions can still
+// lock the comments for sharing
+func (c *CommentModel) FindForShare(conditions ...CommentCondition) ([]*Comment, error) {
+	return (&CommentLocking{model: c}).FindForShare(conditions...)
+}
+
+// SkipLocked leaves out the comments that are already locked, so concurrent
+// workers each claim different comments
+func (c *CommentModel) SkipLocked() *CommentLocking {
+	return &CommentLocking{model: c, wait: lockSkipLocked}
+}
+
+// NoWait fails instead of waiting when a comment is already locked
+func (c *CommentModel) NoWait() *CommentLocking {
+	return &CommentLocking{model: c, wait: lockNoWait}
+}
+
+// CommentLocking is a chaining element for locking comments
+type CommentLocking struct {
+	model *CommentModel
+	wait  lockWait
+}
+
+// FindForUpdate finds the comments matching the conditions and locks them
+// against updates until the transaction ends
+func (l *CommentLocking) FindForUpdate(conditions ...CommentCondition) ([]*Comment, error) {
+	return l.find(lockUpdate, conditions)
+}
+
+// FindForShare finds the comments matching the conditions and locks them
+// against updates, but not other shared locks, until the transaction ends
+func (l *CommentLocking) FindForShare(conditions ...CommentCondition) ([]*Comment, error) {
+	return l.find(lockShare, conditions)
+}
+
+func (l *CommentLocking) find(mode lockMode, conditions []CommentCondition) (comments []*Comment, err error) {
+	op, err := l.model.client.lock(l.model.findMany(mergeCommentConditions(conditions)), mode, l.wait)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.model.client.send(op, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// FindEach calls fn with each comment matching the conditions as it's
+// decoded, without holding the whole result in memory. It stops at the first
+// error returned by fn and returns it.
+func (c *CommentModel) FindEach(ctx context.Context, fn func(*Comment) error, conditions ...CommentCondition) error {
+	op := c.findMany(mergeCommentConditions(conditions))
+	return c.client.stream(ctx, op, func(record json.RawMessage) error {
+		comment := new(Comment)
+		if err := json.Unmarshal(record, comment); err != nil {
+			return err
+		}
+		comment.attach(c.client)
+		return fn(comment)
+	})
+}
+
+// Stream the comments matching the conditions over a channel. The channel
+// is unbuffered, so the comments are only decoded as fast as they're
+// received. Cancel the context to stop early. The error channel receives the
+// result once the comments channel is closed.
+func (c *CommentModel) Stream(ctx context.Context, conditions ...CommentCondition) (<-chan *Comment, <-chan error) {
+	comments := make(chan *Comment)
+	errc := make(chan error, 1)
+	go func() {
+		err := c.FindEach(ctx, func(comment *Comment) error {
+			select {
+			case comments <- comment:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}, conditions...)
+		close(comments)
+		errc <- err
+		close(errc)
+	}()
+	return comments, errc
+}
+
+// Iterate over the comments matching the conditions. Comments are fetched
+// in batches using the After cursor, keeping the order of the conditions.
+func (c *CommentModel) Iterate(ctx context.Context, conditions ...CommentCondition) *CommentIterator {
+	cond := mergeCommentConditions(conditions)
+	return &CommentIterator{
+		model: c,
+		cond:  cond.conditions,
+		pager: newPager(ctx, &cond.conditions),
+	}
+}
+
+// CommentIterator iterates over comments in batches
+type CommentIterator struct {
+	model *CommentModel
+	cond  conditions
+	pager pager
+	batch []*Comment
+	value *Comment
+}
+
+// BatchSize sets how many comments are fetched per query
+func (it *CommentIterator) BatchSize(size int) *CommentIterator {
+	it.pager.size = size
+	return it
+}
+
+// Next advances to the next comment, fetching another batch when needed.
+// It returns false when there are no more comments, the context is done or
+// a query fails.
+func (it *CommentIterator) Next() bool {
+	if !it.pager.check() {
+		return false
+	}
+	if len(it.batch) == 0 {
+		c, ok := it.pager.next(it.cond)
+		if !ok {
+			return false
+		}
+		var comments []*Comment
+		err := it.model.client.sendContext(it.pager.ctx, it.model.findMany(&commentCondition{*c}), &comments)
+		cursor := ""
+		if len(comments) > 0 {
+			cursor = comments[len(comments)-1].ID
+		}
+		it.pager.advance(len(comments), cursor, err)
+		if err != nil || len(comments) == 0 {
+			return false
+		}
+		it.batch = comments
+	}
+	it.value, it.batch = it.batch[0], it.batch[1:]
+	return true
 }
 
-// Create a comment
+// Value returns the current comment
+func (it *CommentIterator) Value() *Comment {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *CommentIterator) Err() error {
+	return it.pager.err
+}
+
+// Count the comments matching the conditions
+func (c *CommentModel) Count(where ...*CommentWhere) (int64, error) {
+	var result BatchPayload
+	op := &operation{
+		name:      "aggregateComment",
+		args:      whereArgs(c.scope, andCommentWhere(where)),
+		selection: "count",
+	}
+	if err := c.client.send(op, &result); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
+// Create a comment. The comment's create hooks run around it.
 func (c *CommentModel) Create(comment *CommentInput) (*Comment, error) {
-	return nil, nil
+	if c.scope != nil {
+		return nil, ErrScoped
+	}
+	if err := commentRules.validate(comment.input(), true); err != nil {
+		return nil, err
+	}
+	// the generated fields are filled in first, so the hooks see them
+	comment = &CommentInput{data: commentRules.stamp(comment.input(), true, c.client.now())}
+	if err := c.client.policies.allow(c.client.Context(), "Comment", comment); err != nil {
+		return nil, err
+	}
+	result, err := c.client.hooked("Comment", hookCreate, comment, func(c *Client) (interface{}, error) {
+		return c.Comment.create(comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Comment), nil
+}
+
+func (c *CommentModel) create(comment *CommentInput) (*Comment, error) {
+	op := &operation{
+		mutation:  true,
+		name:      "createOneComment",
+		args:      object{{"data", comment.input()}},
+		selection: commentFields,
+	}
+	var result *Comment
+	if err := c.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateMany creates comments in bulk and reports how many were created
+func (c *CommentModel) CreateMany(ctx context.Context, comments []*CommentInput, opts *CreateManyOptions) (*BatchPayload, error) {
+	if c.scope != nil {
+		return nil, ErrScoped
+	}
+	rows := make([]object, len(comments))
+	for i, comment := range comments {
+		rows[i] = comment.input()
+	}
+	if err := commentRules.validateRows(rows); err != nil {
+		return nil, err
+	}
+	rows = commentRules.stampRows(rows, c.client.now())
+	for _, row := range rows {
+		if err := c.client.policies.allow(ctx, "Comment", &CommentInput{data: row}); err != nil {
+			return nil, err
+		}
+	}
+	return c.client.createMany(ctx, "Comment", commentRules.unique, rows, opts)
 }
 
-// Update a comment
+// Update a comment. The comment's update hooks run around it.
 func (c *CommentModel) Update(comment *CommentInput, where ...*CommentWhere) (*Comment, error) {
-	return nil, nil
+	if c.scope != nil {
+		return nil, ErrScoped
+	}
+	if err := commentRules.validate(comment.input(), false); err != nil {
+		return nil, err
+	}
+	result, err := c.client.hooked("Comment", hookUpdate, comment, func(c *Client) (interface{}, error) {
+		return c.Comment.update(comment, where)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Comment), nil
+}
+
+func (c *CommentModel) update(comment *CommentInput, where []*CommentWhere) (*Comment, error) {
+	data := commentRules.stamp(commentRules.versioned(comment.input()), false, c.client.now())
+	unique, cond := mergeCommentWhere(where)
+	if len(cond) > 0 {
+		var result *Comment
+		err := c.client.atomically(func(c *Client) (err error) {
+			if err := c.updateIf("Comment", data, unique, cond); err != nil {
+				return err
+			}
+			result, err = c.Comment.Find(&CommentWhere{f: merge(unique, pick(data, commentRules.unique))})
+			return err
+		})
+		return result, err
+	}
+	op := &operation{
+		mutation:  true,
+		name:      "updateOneComment",
+		args:      object{{"data", data}, {"where", unique}},
+		selection: commentFields,
+	}
+	var result *Comment
+	if err := c.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-// UpdateMany a comment
-func (c *CommentModel) UpdateMany(comment *CommentInput, where ...*CommentWhere) (*Comment, error) {
-	return nil, nil
+// UpdateMany updates every comment matching the conditions and reports how
+// many were updated
+func (c *CommentModel) UpdateMany(comment *CommentInput, where ...*CommentWhere) (*BatchPayload, error) {
+	if err := commentRules.validate(comment.input(), false); err != nil {
+		return nil, err
+	}
+	op := &operation{
+		mutation:  true,
+		name:      "updateManyComment",
+		args:      whereArgs(c.scope, andCommentWhere(where)).set("data", commentRules.stamp(commentRules.versioned(comment.input()), false, c.client.now())),
+		selection: "count",
+	}
+	var result BatchPayload
+	if err := c.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
-// Delete a comment
+// Delete a comment. The comment's delete hooks run around it.
 func (c *CommentModel) Delete(where *CommentWhere) (*Comment, error) {
-	return nil, nil
+	if c.scope != nil {
+		return nil, ErrScoped
+	}
+	result, err := c.client.hooked("Comment", hookDelete, where, func(c *Client) (interface{}, error) {
+		return c.Comment.delete(where)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Comment), nil
+}
+
+func (c *CommentModel) delete(where *CommentWhere) (*Comment, error) {
+	op := &operation{
+		mutation:  true,
+		name:      "deleteOneComment",
+		args:      object{{"where", where.filter()}},
+		selection: commentFields,
+	}
+	var result *Comment
+	if err := c.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-// DeleteMany a comment
-func (c *CommentModel) DeleteMany(where *CommentWhere) (*Comment, error) {
-	return nil, nil
+// DeleteMany deletes every comment matching the condition and reports how
+// many were deleted
+func (c *CommentModel) DeleteMany(where *CommentWhere) (*BatchPayload, error) {
+	op := &operation{
+		mutation:  true,
+		name:      "deleteManyComment",
+		args:      whereArgs(c.scope, where.filter()),
+		selection: "count",
+	}
+	var result BatchPayload
+	if err := c.client.send(op, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 // As a comment, find a nested relation
 func (c *CommentModel) As(where *CommentWhere) *CommentAs {
-	return &CommentAs{}
+	scope := and(c.scope, where.filter())
+	return &CommentAs{
+		Post: &PostModel{client: c.client, scope: object{{"comments_some", scope}}},
+		User: &UserModel{client: c.client, scope: object{{"comments_some", scope}}},
+	}
 }
 
 // CommentInput struct
 type CommentInput struct {
+	data object
+}
+
+func (i *CommentInput) input() object {
+	if i == nil {
+		return object{}
+	}
+	return i.data
+}
+
+// ID sets the id of a new comment, which is generated when it isn't set
+func (i *CommentInput) ID(id string) *CommentInput {
+	out := *i
+	out.data = out.data.set("id", id)
+	return &out
+}
+
+// Text CommentInput
+func (i *CommentInput) Text(text string) *CommentInput {
+	out := *i
+	out.data = out.data.set("text", text)
+	return &out
+}
+
+// ConnectPost connects the comment to a post
+func (i *CommentInput) ConnectPost(post *PostConnect) *CommentInput {
+	out := *i
+	out.data = out.data.set("post", object{{"connect", post.where}})
+	return &out
+}
+
+// ConnectWrittenBy connects the comment to its author
+func (i *CommentInput) ConnectWrittenBy(user *UserConnect) *CommentInput {
+	out := *i
+	out.data = out.data.set("writtenBy", object{{"connect", user.where}})
+	return &out
 }
 
 // CommentCondition interface
@@ -738,60 +2882,215 @@ type CommentCondition interface {
 
 // contains comment condition state
 type commentCondition struct {
+	conditions
+}
+
+func mergeCommentConditions(conds []CommentCondition) *commentCondition {
+	var c commentCondition
+	for _, cond := range conds {
+		c.merge(&cond.condition().conditions)
+	}
+	return &c
 }
 
 // CommentWhere struct
 type CommentWhere struct {
-	c *commentCondition
+	f object
+	// cond are the If conditions, which don't identify the comment
+	cond object
 }
 
 var _ CommentCondition = (*CommentWhere)(nil)
 
 // ID condition
 func (w *CommentWhere) ID(id string) *CommentWhere {
-	return w
+	out := *w
+	out.f = out.f.set("id", id)
+	return &out
 }
 
-// Email condition
+// Email condition on the comment's author
 func (w *CommentWhere) Email(email string) *CommentWhere {
-	return w
+	out := *w
+	out.f = out.f.set("writtenBy", object{{"email", email}})
+	return &out
 }
 
 // CreatedAtLt condition
 func (w *CommentWhere) CreatedAtLt(createdAt time.Time) *CommentWhere {
-	return w
+	out := *w
+	out.f = out.f.set("createdAt_lt", createdAt)
+	return &out
+}
+
+// CreatedAtGt condition
+func (w *CommentWhere) CreatedAtGt(createdAt time.Time) *CommentWhere {
+	out := *w
+	out.f = out.f.set("createdAt_gt", createdAt)
+	return &out
 }
 
 func (w *CommentWhere) condition() *commentCondition {
-	return w.c
+	return &commentCondition{conditions{where: w.filter()}}
+}
+
+func (w *CommentWhere) filter() object {
+	if w == nil {
+		return nil
+	}
+	return and(w.f, w.cond)
+}
+
+// If requires the conditions to still hold when updating the comment. The
+// update fails with ErrConflict when they don't. The updated comment is read
+// back in the same transaction when the transport supports them, otherwise
+// a concurrent write in between can make the update return ErrNotFound or
+// another write's comment.
+func (w *CommentWhere) If(conditions ...*CommentWhere) *CommentWhere {
+	out := *w
+	out.cond = and(out.cond, andCommentWhere(conditions))
+	return &out
+}
+
+// mergeCommentWhere merges unique conditions into a single where, leaving out
+// the If conditions
+func mergeCommentWhere(where []*CommentWhere) (unique object, cond object) {
+	var filters, conds []object
+	for _, w := range where {
+		if w != nil {
+			filters = append(filters, w.f)
+			conds = append(conds, w.cond)
+		}
+	}
+	return merge(filters...), and(conds...)
+}
+
+// andCommentWhere requires all of the conditions to match
+func andCommentWhere(where []*CommentWhere) object {
+	filters := make([]object, len(where))
+	for i, w := range where {
+		filters[i] = w.filter()
+	}
+	return and(filters...)
 }
 
 // CommentOrder struct
 type CommentOrder struct {
-	c *commentCondition
+	o object
 }
 
 var _ CommentCondition = (*CommentOrder)(nil)
 
+// ID condition
+func (w *CommentOrder) ID(order OrderBy) *CommentOrder {
+	out := *w
+	out.o = out.o.set("id", order)
+	return &out
+}
+
 // CreatedAt condition
 func (w *CommentOrder) CreatedAt(order OrderBy) *CommentOrder {
-	return w
+	out := *w
+	out.o = out.o.set("createdAt", order)
+	return &out
 }
 
 func (w *CommentOrder) condition() *commentCondition {
-	return w.c
+	return &commentCondition{conditions{orderBy: w.o}}
+}
+
+// CommentDistinct condition
+type CommentDistinct []CommentField
+
+var _ CommentCondition = (*CommentDistinct)(nil)
+
+func (w *CommentDistinct) condition() *commentCondition {
+	fields := make([]enum, len(*w))
+	for i, field := range *w {
+		fields[i] = field
+	}
+	return &commentCondition{conditions{distinct: fields}}
+}
+
+// CommentFirst condition
+type CommentFirst int
+
+func (w *CommentFirst) condition() *commentCondition {
+	first := int(*w)
+	return &commentCondition{conditions{first: &first}}
+}
+
+// CommentAfter condition
+type CommentAfter string
+
+func (w *CommentAfter) condition() *commentCondition {
+	after := string(*w)
+	return &commentCondition{conditions{after: &after}}
+}
+
+// CommentBefore condition
+type CommentBefore string
+
+func (w *CommentBefore) condition() *commentCondition {
+	before := string(*w)
+	return &commentCondition{conditions{before: &before}}
+}
+
+// CommentSkip condition
+type CommentSkip int
+
+func (w *CommentSkip) condition() *commentCondition {
+	skip := int(*w)
+	return &commentCondition{conditions{skip: &skip}}
+}
+
+// CommentLast condition
+type CommentLast int
+
+func (w *CommentLast) condition() *commentCondition {
+	last := int(*w)
+	return &commentCondition{conditions{last: &last}}
 }
 
 // CommentWith struct
 type CommentWith struct {
+	with []*include
+}
+
+var _ CommentCondition = (*CommentWith)(nil)
+
+// Post includes the comment's post
+func (p *CommentWith) Post(with ...*PostWith) *CommentWith {
+	out := *p
+	var c postCondition
+	for _, w := range with {
+		c.merge(&w.condition().conditions)
+	}
+	out.with = includes(out.with, &include{relation: "post", args: nil, fields: postFields, nested: &c.conditions})
+	return &out
+}
+
+// WrittenBy includes the comment's author
+func (p *CommentWith) WrittenBy(with ...*UserWith) *CommentWith {
+	out := *p
+	var c userCondition
+	for _, w := range with {
+		c.merge(&w.condition().conditions)
+	}
+	out.with = includes(out.with, &include{relation: "writtenBy", args: nil, fields: userFields, nested: &c.conditions})
+	return &out
 }
 
 func (p *CommentWith) condition() *commentCondition {
-	return &commentCondition{}
+	return &commentCondition{conditions{with: p.with}}
 }
 
 // CommentAs struct
 type CommentAs struct {
+	// Post through the comment's post
+	Post *PostModel
+	// User through the comment's author
+	User *UserModel
 }
 
 // Conn struct
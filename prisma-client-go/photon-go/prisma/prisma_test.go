@@ -0,0 +1,57 @@
+package prisma
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// testDB records the queries it's sent and answers them with respond, or
+// with a null result when respond is nil. Queries fail when fail returns an
+// error.
+type testDB struct {
+	mu      sync.Mutex
+	queries []string
+	respond func(query string) string
+	fail    func(query string) error
+}
+
+var _ DB = (*testDB)(nil)
+
+func (db *testDB) Send(ctx context.Context, query string, result interface{}) error {
+	db.record(query)
+	if db.fail != nil {
+		if err := db.fail(query); err != nil {
+			return err
+		}
+	}
+	data := "null"
+	if db.respond != nil {
+		data = db.respond(query)
+	}
+	return json.Unmarshal([]byte(`{"result": `+data+`}`), result)
+}
+
+func (db *testDB) Close() error {
+	return nil
+}
+
+// record a query without answering it
+func (db *testDB) record(query string) {
+	db.mu.Lock()
+	db.queries = append(db.queries, query)
+	db.mu.Unlock()
+}
+
+// sent returns the queries sent so far
+func (db *testDB) sent() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return append([]string{}, db.queries...)
+}
+
+// testClient returns a client sending its queries to a testDB
+func testClient(respond func(query string) string) (*Client, *testDB) {
+	db := &testDB{respond: respond}
+	return newClient(db), db
+}
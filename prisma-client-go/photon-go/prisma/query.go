@@ -0,0 +1,434 @@
+package prisma
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Find when no record matches
+var ErrNotFound = errors.New("prisma: record not found")
+
+// ErrConflict is returned by conditional updates when the record no longer
+// matches the If conditions, usually because someone else changed it
+var ErrConflict = errors.New("prisma: record was changed since it was read")
+
+// ErrScoped is returned by writes that can't be scoped to a relation
+var ErrScoped = errors.New("prisma: operation is not supported through As")
+
+// ErrDetached is returned when lazily loading the relations of a model that
+// wasn't returned by a client
+var ErrDetached = errors.New("prisma: model wasn't returned by a client")
+
+// EngineError is returned when the Prisma Engine rejects a query
+type EngineError struct {
+	Message string `json:"message"`
+	Code    string `json:"code"` // Code is the engine's error code, like P2002
+}
+
+// Error implements error
+func (e *EngineError) Error() string {
+	return "prisma: " + e.Message
+}
+
+// field is a single key-value pair within an object
+type field struct {
+	name  string
+	value interface{}
+}
+
+// object is an ordered input object sent to the Prisma Engine. We keep the
+// order so the same conditions always compile to the same query.
+type object []field
+
+// get a field by name
+func (o object) get(name string) (interface{}, bool) {
+	for _, f := range o {
+		if f.name == name {
+			return f.value, true
+		}
+	}
+	return nil, false
+}
+
+// set a field, replacing an existing field with the same name. The object
+// is never modified, so objects derived from the same object can't affect
+// each other or the object they were derived from.
+func (o object) set(name string, value interface{}) object {
+	for i, f := range o {
+		if f.name == name {
+			out := append(object{}, o...)
+			out[i].value = value
+			return out
+		}
+	}
+	return append(o[:len(o):len(o)], field{name, value})
+}
+
+// push appends objects to the list stored under name.op, which is how
+// nested writes like {posts: {create: [...]}} are built up
+func (o object) push(name, op string, values ...object) object {
+	nested, _ := o.get(name)
+	inner, _ := nested.(object)
+	list, _ := inner.get(op)
+	prev, _ := list.([]object)
+	return o.set(name, inner.set(op, append(append([]object{}, prev...), values...)))
+}
+
+// also appends a filter to the AND list, for filters on the same field that
+// mustn't replace each other
+func (o object) also(filter object) object {
+	prev, _ := o.get("AND")
+	list, _ := prev.([]object)
+	return o.set("AND", append(append([]object{}, list...), filter))
+}
+
+// and combines filters, skipping the empty ones
+func and(filters ...object) object {
+	var nonempty []object
+	for _, f := range filters {
+		if len(f) > 0 {
+			nonempty = append(nonempty, f)
+		}
+	}
+	switch len(nonempty) {
+	case 0:
+		return nil
+	case 1:
+		return nonempty[0]
+	default:
+		return object{{"AND", nonempty}}
+	}
+}
+
+// enum values are sent to the engine without quotes
+type enum interface {
+	enum() string
+}
+
+// operation is a single root-level call to the Prisma Engine
+type operation struct {
+	mutation  bool
+	name      string
+	args      object
+	selection string
+	// related are the relations selected along with the selected fields
+	related []*related
+}
+
+// related is a relation selected along with records, either the related
+// records or their number
+type related struct {
+	// key of the relation in the result, counts are aliased
+	key      string
+	relation string
+	args     object
+	count    bool
+	// fields and related are what's selected of the related records
+	fields  string
+	related []*related
+}
+
+// encodeRelated writes the fields followed by the relations selected along
+// with them
+func encodeRelated(b *strings.Builder, fields string, list []*related) {
+	b.WriteString(fields)
+	for i, r := range list {
+		if fields != "" || i > 0 {
+			b.WriteString(" ")
+		}
+		if r.count {
+			b.WriteString(r.key)
+			b.WriteString(": _count { ")
+			b.WriteString(r.relation)
+			writeArgs(b, r.args)
+			b.WriteString(" }")
+			continue
+		}
+		b.WriteString(r.relation)
+		writeArgs(b, r.args)
+		b.WriteString(" { ")
+		encodeRelated(b, r.fields, r.related)
+		b.WriteString(" }")
+	}
+}
+
+// writeArgs writes the arguments of a field, if it has any
+func writeArgs(b *strings.Builder, args object) {
+	if len(args) > 0 {
+		b.WriteString("(")
+		encodeFields(b, args)
+		b.WriteString(")")
+	}
+}
+
+// String compiles the operation into a query. The result is aliased so we
+// can decode every operation the same way.
+func (o *operation) String() string {
+	var b strings.Builder
+	if o.mutation {
+		b.WriteString("mutation")
+	} else {
+		b.WriteString("query")
+	}
+	b.WriteString(" { result: ")
+	b.WriteString(o.name)
+	if len(o.args) > 0 {
+		b.WriteString("(")
+		encodeFields(&b, o.args)
+		b.WriteString(")")
+	}
+	if o.selection != "" || len(o.related) > 0 {
+		b.WriteString(" { ")
+		encodeRelated(&b, o.selection, o.related)
+		b.WriteString(" }")
+	}
+	b.WriteString(" }")
+	return b.String()
+}
+
+func encodeFields(b *strings.Builder, o object) {
+	for i, f := range o {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(f.name)
+		b.WriteString(": ")
+		encode(b, f.value)
+	}
+}
+
+// encode a Go value as an engine input value
+func encode(b *strings.Builder, v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case object:
+		b.WriteString("{")
+		encodeFields(b, v)
+		b.WriteString("}")
+	case enum:
+		b.WriteString(v.enum())
+	case string:
+		s, _ := json.Marshal(v)
+		b.Write(s)
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case int:
+		b.WriteString(strconv.Itoa(v))
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case time.Time:
+		b.WriteString(strconv.Quote(v.UTC().Format(time.RFC3339Nano)))
+	case Decimal:
+		b.WriteString(strconv.Quote(v.String()))
+	case BigInt:
+		b.WriteString(strconv.FormatInt(int64(v), 10))
+	case JSON:
+		// the engine takes JSON values as strings
+		if len(v) == 0 {
+			b.WriteString("null")
+			break
+		}
+		encode(b, string(v))
+	case []byte:
+		encode(b, base64.StdEncoding.EncodeToString(v))
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			panic(fmt.Sprintf("prisma: unable to encode %T", v))
+		}
+		b.WriteString("[")
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			encode(b, rv.Index(i).Interface())
+		}
+		b.WriteString("]")
+	}
+}
+
+// pick the named fields of an object
+func pick(o object, names []string) (out object) {
+	for _, name := range names {
+		if value, ok := o.get(name); ok {
+			out = out.set(name, value)
+		}
+	}
+	return out
+}
+
+// merge objects into one, later fields override earlier ones
+func merge(objects ...object) object {
+	var out object
+	for _, o := range objects {
+		for _, f := range o {
+			out = out.set(f.name, f.value)
+		}
+	}
+	return out
+}
+
+// conditions are the read arguments shared by every model
+type conditions struct {
+	where    object
+	orderBy  object
+	distinct []enum
+	first    *int
+	last     *int
+	skip     *int
+	after    *string
+	before   *string
+	with     []*include
+	// counts are the filters of the relations counted by Select
+	counts []*include
+	// deleted is whether soft deleted records match
+	deleted deletedScope
+}
+
+// distinct adds fields to the list, skipping the ones already in it
+func distinct(list []enum, fields ...enum) []enum {
+	out := append([]enum{}, list...)
+outer:
+	for _, f := range fields {
+		for _, prev := range out {
+			if prev.enum() == f.enum() {
+				continue outer
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// include is a relation loaded along with the records
+type include struct {
+	relation string
+	args     object
+	fields   string
+	// nested are the conditions of the related records
+	nested *conditions
+}
+
+// includes adds the relation to the list, replacing an earlier include of
+// the same relation
+func includes(list []*include, with ...*include) []*include {
+	out := append([]*include{}, list...)
+outer:
+	for _, w := range with {
+		for i, prev := range out {
+			if prev.relation == w.relation {
+				out[i] = w
+				continue outer
+			}
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// merge in another set of conditions. Filters are ANDed together, orderings
+// are appended after the earlier ones, which take precedence, and later
+// pagination arguments override earlier ones.
+func (c *conditions) merge(o *conditions) {
+	c.where = and(c.where, o.where)
+	c.orderBy = merge(c.orderBy, o.orderBy)
+	c.distinct = distinct(c.distinct, o.distinct...)
+	if o.first != nil {
+		c.first = o.first
+	}
+	if o.last != nil {
+		c.last = o.last
+	}
+	if o.skip != nil {
+		c.skip = o.skip
+	}
+	if o.after != nil {
+		c.after = o.after
+	}
+	if o.before != nil {
+		c.before = o.before
+	}
+	c.with = includes(c.with, o.with...)
+	c.counts = includes(c.counts, o.counts...)
+	if o.deleted != excludeDeleted {
+		c.deleted = o.deleted
+	}
+}
+
+// related returns the included relations to select along with the fields
+func (c *conditions) related() []*related {
+	var list []*related
+	for _, w := range c.with {
+		r := &related{key: w.relation, relation: w.relation, args: w.args, fields: w.fields}
+		if w.nested != nil {
+			r.related = w.nested.related()
+		}
+		list = append(list, r)
+	}
+	return list
+}
+
+// args compiles the conditions within a relation scope
+func (c *conditions) args(scope object) object {
+	args := whereArgs(scope, c.where)
+	if len(c.orderBy) > 0 {
+		orderBy := c.orderBy
+		// ties are broken by id, so records are always returned in the
+		// same order and cursors don't skip or repeat records
+		if _, ok := orderBy.get("id"); !ok {
+			orderBy = orderBy.set("id", ASC)
+		}
+		args = args.set("orderBy", orderList(orderBy))
+	}
+	if len(c.distinct) > 0 {
+		args = args.set("distinct", c.distinct)
+	}
+	if c.skip != nil {
+		args = args.set("skip", *c.skip)
+	}
+	if c.after != nil {
+		args = args.set("after", *c.after)
+	}
+	if c.before != nil {
+		args = args.set("before", *c.before)
+	}
+	if c.first != nil {
+		args = args.set("first", *c.first)
+	}
+	if c.last != nil {
+		args = args.set("last", *c.last)
+	}
+	return args
+}
+
+// BatchPayload reports how many records a bulk write affected
+type BatchPayload struct {
+	Count int64 `json:"count"`
+}
+
+// orderList compiles orderings into a list of single field orderings, so
+// the engine sorts by the fields in the order they were added
+func orderList(orderBy object) []object {
+	list := make([]object, len(orderBy))
+	for i, f := range orderBy {
+		list[i] = object{f}
+	}
+	return list
+}
+
+// whereArgs builds the arguments for operations that only take a filter
+func whereArgs(scope, where object) (args object) {
+	if where := and(scope, where); len(where) > 0 {
+		args = args.set("where", where)
+	}
+	return args
+}
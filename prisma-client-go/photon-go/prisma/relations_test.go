@@ -0,0 +1,136 @@
+package prisma
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAs(t *testing.T) {
+	client, db := testClient(func(string) string { return `[]` })
+	if _, err := client.User.As((&UserWhere{}).ID("u1")).Post.FindMany((&PostWhere{}).Published(true)); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	if !strings.Contains(query, `author: {id: "u1"}`) || !strings.Contains(query, "published: true") {
+		t.Fatalf("expected the posts of u1 in %s", query)
+	}
+	if len(db.sent()) != 1 {
+		t.Fatalf("expected a single query, got %v", db.sent())
+	}
+}
+
+func TestWith(t *testing.T) {
+	client, db := testClient(func(string) string {
+		return `[{"id": "p1", "author": null, "comments": [{"id": "c1", "text": "hi"}]}]`
+	})
+	posts, err := client.Post.FindMany((&PostWith{}).Author(), (&PostWith{}).Comments())
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	if !strings.Contains(query, "author { ") || !strings.Contains(query, "comments { ") {
+		t.Fatalf("expected the author and comments to be selected in %s", query)
+	}
+	p := posts[0]
+	if !p.Relations.AuthorLoaded || p.Relations.Author != nil {
+		t.Fatalf("expected a missing author to be loaded, got %+v", p.Relations)
+	}
+	// included relations are returned without a query
+	if _, err := p.Author(context.Background()); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	comments, err := p.Comments(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].Text != "hi" {
+		t.Fatalf("expected the included comment, got %v", comments)
+	}
+	if len(db.sent()) != 1 {
+		t.Fatalf("expected included relations not to be queried, got %v", db.sent())
+	}
+}
+
+func TestLazyLoad(t *testing.T) {
+	client, db := testClient(func(query string) string {
+		switch {
+		case strings.Contains(query, "findManyPost"):
+			return `[{"id": "p1"}]`
+		case strings.Contains(query, "findManyUser"):
+			return `[{"id": "u1", "email": "ada@prisma.io"}]`
+		}
+		return `{"id": "u1"}`
+	})
+	ctx := context.Background()
+	client = client.WithContext(ctx)
+	p, err := client.Post.Find((&PostWhere{}).ID("p1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		author, err := p.Author(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if author.Email != "ada@prisma.io" {
+			t.Fatalf("expected the author, got %+v", author)
+		}
+	}
+	sent := db.sent()
+	if len(sent) != 2 || !strings.Contains(sent[1], `posts_some: {id: "p1"}`) {
+		t.Fatalf("expected the second load to hit the cache, got %v", sent)
+	}
+	// a write clears the cache
+	if _, err := client.User.Update((&UserInput{}).Name("Ada"), (&UserWhere{}).ID("u1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Author(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sent := db.sent(); len(sent) != 4 || sent[3] != sent[1] {
+		t.Fatalf("expected the author to be loaded again, got %v", sent)
+	}
+}
+
+func TestLazyLoadNotFound(t *testing.T) {
+	client, _ := testClient(func(query string) string {
+		if strings.Contains(query, "findManyComment") {
+			return `[{"id": "c1"}]`
+		}
+		return `[]`
+	})
+	ctx := context.Background()
+	c, err := client.Comment.Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Post(ctx); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for the post, got %v", err)
+	}
+	if _, err := c.WrittenBy(ctx); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for the author, got %v", err)
+	}
+	p := &Post{ID: "p1", client: client}
+	if _, err := p.Author(ctx); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for a post without an author, got %v", err)
+	}
+}
+
+func TestLazyLoadDetached(t *testing.T) {
+	ctx := context.Background()
+	if _, err := (&Post{ID: "p1"}).Author(ctx); err != ErrDetached {
+		t.Fatalf("expected ErrDetached, got %v", err)
+	}
+	if _, err := (&User{ID: "u1"}).Posts(ctx); err != ErrDetached {
+		t.Fatalf("expected ErrDetached, got %v", err)
+	}
+	if _, err := (&Comment{ID: "c1"}).Post(ctx); err != ErrDetached {
+		t.Fatalf("expected ErrDetached, got %v", err)
+	}
+	// included relations don't need a client
+	u := &User{ID: "u1", Relations: UserRelations{Posts: []*Post{}}}
+	if posts, err := u.Posts(ctx); err != nil || posts == nil {
+		t.Fatalf("expected the included posts, got %v, %v", posts, err)
+	}
+}
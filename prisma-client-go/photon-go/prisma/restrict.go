@@ -0,0 +1,343 @@
+package prisma
+
+import (
+	"context"
+	"strings"
+)
+
+// restriction filters the records of models wherever a query reaches them
+type restriction interface {
+	// filter returns the filter of model, nil when its records aren't
+	// filtered
+	filter(model string) (object, error)
+}
+
+// restrict applies r to every filter of op: its where, the relation filters
+// within it, the relations included along with its records, the relations
+// they count and the relation counts they're ordered by. Creates don't
+// filter records, so only their selection is restricted.
+func restrict(r restriction, op *operation) (*operation, error) {
+	kind, model, ok := splitOperation(op.name)
+	if !ok {
+		return op, nil
+	}
+	args, err := restrictArgs(r, model, op.args, kind.unique, !kind.create())
+	if err != nil {
+		return nil, err
+	}
+	list, err := restrictRelated(r, model, op.related)
+	if err != nil {
+		return nil, err
+	}
+	out := *op
+	out.args = args
+	out.related = list
+	return &out, nil
+}
+
+// restrictArgs restricts the where and orderBy arguments of a read of
+// model. Reads that filter records are restricted even without a where.
+func restrictArgs(r restriction, model string, args object, unique, filter bool) (object, error) {
+	out := args
+	if orderBy, ok := args.get("orderBy"); ok {
+		list, err := restrictOrder(r, model, orderBy)
+		if err != nil {
+			return nil, err
+		}
+		out = out.set("orderBy", list)
+	}
+	if !filter {
+		return out, nil
+	}
+	value, _ := args.get("where")
+	where, _ := value.(object)
+	where, err := restrictWhere(r, model, where, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(where) > 0 {
+		out = out.set("where", where)
+	}
+	return out, nil
+}
+
+// restrictWhere restricts a filter on model and the relation filters within
+// it. A unique where can only hold the fields of a unique constraint, so
+// it's left as it is and sendVerified checks the record it identifies.
+func restrictWhere(r restriction, model string, where object, unique bool) (object, error) {
+	where, err := restrictRelations(r, model, where)
+	if err != nil || unique {
+		return where, err
+	}
+	filter, err := r.filter(model)
+	if err != nil || len(filter) == 0 {
+		return where, err
+	}
+	return and(where, filter), nil
+}
+
+// restrictRelations restricts the relation filters within a filter on
+// model, like posts_some or author
+func restrictRelations(r restriction, model string, where object) (object, error) {
+	out := where
+	for _, f := range where {
+		var value interface{}
+		switch f.name {
+		case "AND", "OR", "NOT":
+			list, err := each(f.value, func(filter object) (object, error) {
+				return restrictRelations(r, model, filter)
+			})
+			if err != nil {
+				return nil, err
+			}
+			value = list
+		default:
+			related := modelRules[model].relation(relationName(f.name))
+			nested, ok := f.value.(object)
+			if related == "" || !ok {
+				continue
+			}
+			restricted, err := restrictWhere(r, related, nested, false)
+			if err != nil {
+				return nil, err
+			}
+			value = restricted
+		}
+		out = out.set(f.name, value)
+	}
+	return out, nil
+}
+
+// relationName strips the suffix of a to-many relation filter
+func relationName(filter string) string {
+	for _, suffix := range []string{"_some", "_every", "_none"} {
+		if strings.HasSuffix(filter, suffix) {
+			return strings.TrimSuffix(filter, suffix)
+		}
+	}
+	return filter
+}
+
+// restrictOrder restricts the records counted by the relation counts an
+// ordering of model sorts by
+func restrictOrder(r restriction, model string, orderBy interface{}) (interface{}, error) {
+	return each(orderBy, func(order object) (object, error) {
+		out := order
+		for _, f := range order {
+			related := modelRules[model].many(f.name)
+			count, ok := f.value.(object)
+			if related == "" || !ok {
+				continue
+			}
+			if _, ok := count.get("count"); !ok {
+				continue
+			}
+			value, _ := count.get("where")
+			where, _ := value.(object)
+			where, err := restrictWhere(r, related, where, false)
+			if err != nil {
+				return nil, err
+			}
+			if len(where) > 0 {
+				out = out.set(f.name, count.set("where", where))
+			}
+		}
+		return out, nil
+	})
+}
+
+// restrictRelated restricts the relations selected along with the records
+// of model. To-many relations and their counts only include the records r
+// allows. A to-one relation can't be filtered, it's included as long as
+// the record holding it is.
+func restrictRelated(r restriction, model string, list []*related) ([]*related, error) {
+	if len(list) == 0 {
+		return list, nil
+	}
+	out := make([]*related, len(list))
+	for i, rel := range list {
+		restricted := *rel
+		target := modelRules[model].many(rel.relation)
+		if target != "" {
+			args, err := restrictArgs(r, target, rel.args, false, true)
+			if err != nil {
+				return nil, err
+			}
+			restricted.args = args
+		} else if target = modelRules[model].relation(rel.relation); target == "" {
+			out[i] = rel
+			continue
+		}
+		if !rel.count {
+			nested, err := restrictRelated(r, target, rel.related)
+			if err != nil {
+				return nil, err
+			}
+			restricted.related = nested
+		}
+		out[i] = &restricted
+	}
+	return out, nil
+}
+
+// each calls fn with an object or with each object of a list, and returns
+// the results in the same shape
+func each(value interface{}, fn func(object) (object, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case object:
+		return fn(v)
+	case []object:
+		out := make([]object, len(v))
+		for i, o := range v {
+			var err error
+			if out[i], err = fn(o); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// check is a unique where on a model whose records are restricted
+type check struct {
+	model string
+	where object
+}
+
+// restricted reports whether the records of model are filtered by a policy
+// or by the tenancy
+func (c *Client) restricted(model string) bool {
+	if c.tenancy != nil {
+		if _, ok := c.tenancy.Fields[model]; ok {
+			return true
+		}
+	}
+	c.policies.mu.RLock()
+	defer c.policies.mu.RUnlock()
+	return len(c.policies.where[model]) > 0
+}
+
+// unverified reports whether op has unique wheres on restricted models,
+// which sendVerified checks before sending it
+func (c *Client) unverified(op *operation) bool {
+	if _, ok := c.unique(op); ok {
+		return true
+	}
+	return len(c.connects(op)) > 0
+}
+
+// unique returns the where of a unique write on a restricted model
+func (c *Client) unique(op *operation) (check, bool) {
+	kind, model, ok := splitOperation(op.name)
+	if !ok || !kind.unique || !c.restricted(model) {
+		return check{}, false
+	}
+	value, _ := op.args.get("where")
+	where, _ := value.(object)
+	return check{model, where}, true
+}
+
+// connects returns the connects of the nested writes of op on models whose
+// records are scoped by the tenancy
+func (c *Client) connects(op *operation) []check {
+	_, model, ok := splitOperation(op.name)
+	if !ok || c.tenancy == nil {
+		return nil
+	}
+	var list []check
+	for _, name := range []string{"data", "create", "update"} {
+		if data, ok := op.args.get(name); ok {
+			list = c.nestedConnects(model, data, list)
+		}
+	}
+	return list
+}
+
+// nestedConnects appends the connects within the data of a write on model,
+// including those of nested creates, to list
+func (c *Client) nestedConnects(model string, data interface{}, list []check) []check {
+	each(data, func(data object) (object, error) {
+		for _, rule := range modelRules[model].fields {
+			value, _ := data.get(rule.field)
+			nested, _ := value.(object)
+			if rule.relation == "" || nested == nil {
+				continue
+			}
+			if connect, ok := nested.get("connect"); ok {
+				if _, scoped := c.tenancy.Fields[rule.relation]; scoped {
+					each(connect, func(where object) (object, error) {
+						list = append(list, check{rule.relation, where})
+						return where, nil
+					})
+				}
+			}
+			if create, ok := nested.get("create"); ok {
+				list = c.nestedConnects(rule.relation, create, list)
+			}
+		}
+		return data, nil
+	})
+	return list
+}
+
+// sendVerified sends op once the records its unique wheres identify are
+// found among the records their models are restricted to, like any other
+// filter would, and fails with ErrNotFound otherwise. An upsert whose
+// record isn't found becomes a create, and an update when it is.
+func (c *Client) sendVerified(ctx context.Context, op *operation, v interface{}) error {
+	if unique, ok := c.unique(op); ok {
+		found, err := c.exists(ctx, unique)
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(op.name, "upsertOne"):
+			op = upserted(op, unique.model, found)
+		case !found:
+			return ErrNotFound
+		}
+	}
+	for _, connect := range c.connects(op) {
+		found, err := c.exists(ctx, connect)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrNotFound
+		}
+	}
+	return c.sendCompiled(ctx, op, v)
+}
+
+// exists counts the records matching a check, with the restrictions of its
+// model
+func (c *Client) exists(ctx context.Context, chk check) (bool, error) {
+	op := &operation{
+		name:      "aggregate" + chk.model,
+		args:      whereArgs(nil, chk.where),
+		selection: "count",
+	}
+	var result BatchPayload
+	if err := c.sendCompiled(ctx, op, &result); err != nil {
+		return false, err
+	}
+	return result.Count > 0, nil
+}
+
+// upserted turns an upsert of model into the update or the create it
+// amounts to
+func upserted(op *operation, model string, found bool) *operation {
+	out := *op
+	if found {
+		where, _ := op.args.get("where")
+		data, _ := op.args.get("update")
+		out.name = "updateOne" + model
+		out.args = object{{"data", data}, {"where", where}}
+	} else {
+		data, _ := op.args.get("create")
+		out.name = "createOne" + model
+		out.args = object{{"data", data}}
+	}
+	return &out
+}
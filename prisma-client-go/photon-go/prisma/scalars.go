@@ -0,0 +1,131 @@
+package prisma
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+// Decimal is an arbitrary precision decimal. It keeps the exact digits it
+// was created with, so nothing is lost between Go and the engine.
+type Decimal struct {
+	s string
+}
+
+var decimalPattern = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)([eE][-+]?\d+)?$`)
+
+// ParseDecimal parses a decimal like "19.99"
+func ParseDecimal(s string) (Decimal, error) {
+	if !decimalPattern.MatchString(s) {
+		return Decimal{}, fmt.Errorf("prisma: invalid decimal %q", s)
+	}
+	return Decimal{s}, nil
+}
+
+// MustDecimal is like ParseDecimal but panics if s isn't a decimal
+func MustDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromRat converts an exact value to a decimal with scale digits
+// after the decimal point
+func DecimalFromRat(r *big.Rat, scale int) Decimal {
+	return Decimal{r.FloatString(scale)}
+}
+
+// String returns the decimal's digits
+func (d Decimal) String() string {
+	if d.s == "" {
+		return "0"
+	}
+	return d.s
+}
+
+// Rat returns the exact value of the decimal
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// MarshalJSON encodes the decimal as a string so JSON parsers don't round it
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts the decimal as a string or a number without going
+// through float64. Null is zero, use NullDecimal to tell them apart.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// BigInt is a 64-bit integer. It's encoded as a string in JSON because
+// many JSON parsers can't represent it exactly.
+type BigInt int64
+
+// MarshalJSON implements json.Marshaler
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(b), 10))
+}
+
+// UnmarshalJSON accepts the integer as a string or a number. Null is zero,
+// use NullBigInt to tell them apart.
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*b = 0
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("prisma: invalid BigInt %s", data)
+	}
+	*b = BigInt(n)
+	return nil
+}
+
+// JSON is a raw JSON value. An empty JSON value is null.
+type JSON json.RawMessage
+
+// MarshalJSON implements json.Marshaler
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return null, nil
+	}
+	return j, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*j = nil
+		return nil
+	}
+	*j = append((*j)[:0], data...)
+	return nil
+}
@@ -0,0 +1,164 @@
+package prisma
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// roundTrip encodes v as an engine input value and decodes the result
+// into out, like a value sent to the engine and read back
+func roundTrip(t *testing.T, v interface{}, out json.Unmarshaler) string {
+	t.Helper()
+	var b strings.Builder
+	encode(&b, v)
+	if err := out.UnmarshalJSON([]byte(b.String())); err != nil {
+		t.Fatalf("decoding %s: %v", b.String(), err)
+	}
+	return b.String()
+}
+
+func TestDecimalRoundTrip(t *testing.T) {
+	for _, digits := range []string{
+		// more digits than a float64 can hold
+		"12345678901234567890.123456789012345678",
+		"0.30000000000000000001",
+		"-99999999999999999999999999.000000000000000001",
+	} {
+		var d Decimal
+		encoded := roundTrip(t, MustDecimal(digits), &d)
+		if encoded != `"`+digits+`"` {
+			t.Fatalf("expected %s to be sent as a string, got %s", digits, encoded)
+		}
+		if d.String() != digits {
+			t.Fatalf("expected %s, got %s", digits, d)
+		}
+		// the engine may return decimals as strings or numbers
+		for _, data := range []string{`"` + digits + `"`, digits} {
+			var d Decimal
+			if err := json.Unmarshal([]byte(data), &d); err != nil {
+				t.Fatal(err)
+			}
+			if d.String() != digits {
+				t.Fatalf("decoding %s: expected %s, got %s", data, digits, d)
+			}
+			out, err := json.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != `"`+digits+`"` {
+				t.Fatalf("expected %q, got %s", digits, out)
+			}
+		}
+	}
+}
+
+func TestDecimalQuery(t *testing.T) {
+	balance := MustDecimal("0.30000000000000000001")
+	var b strings.Builder
+	encode(&b, (&UserWhere{}).BalanceGt(balance).f)
+	if want := `{balance_gt: "0.30000000000000000001"}`; b.String() != want {
+		t.Fatalf("expected %s, got %s", want, b.String())
+	}
+}
+
+func TestBigIntRoundTrip(t *testing.T) {
+	for _, n := range []BigInt{
+		// past the integers a float64 holds exactly
+		1<<53 + 1,
+		-(1<<53 + 1),
+		math.MaxInt64,
+		math.MinInt64,
+	} {
+		var got BigInt
+		encoded := roundTrip(t, n, &got)
+		if got != n {
+			t.Fatalf("expected %d to round trip through %s, got %d", n, encoded, got)
+		}
+		out, err := json.Marshal(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// results are read back from strings too
+		got = 0
+		if err := json.Unmarshal(out, &got); err != nil || got != n {
+			t.Fatalf("expected %d to round trip through %s, got %d, %v", n, out, got, err)
+		}
+	}
+	var b BigInt
+	if err := json.Unmarshal([]byte(`"9223372036854775808"`), &b); err == nil {
+		t.Fatal("expected an error for an integer out of range")
+	}
+}
+
+func TestBigIntQuery(t *testing.T) {
+	var b strings.Builder
+	encode(&b, (&PostWhere{}).ImpressionsGt(1<<53+1).f)
+	if want := `{impressions_gt: 9007199254740993}`; b.String() != want {
+		t.Fatalf("expected %s, got %s", want, b.String())
+	}
+}
+
+func TestJSONAndBytes(t *testing.T) {
+	var b strings.Builder
+	encode(&b, object{{"metadata", JSON(`{"n": 9007199254740993}`)}, {"avatar", []byte{0, 1, 2}}, {"empty", JSON(nil)}})
+	if want := `{metadata: "{\"n\": 9007199254740993}", avatar: "AAEC", empty: null}`; b.String() != want {
+		t.Fatalf("expected %s, got %s", want, b.String())
+	}
+	var j JSON
+	if err := json.Unmarshal([]byte(`{"n": 9007199254740993}`), &j); err != nil {
+		t.Fatal(err)
+	}
+	if string(j) != `{"n": 9007199254740993}` {
+		t.Fatalf("expected the raw value to be kept, got %s", j)
+	}
+}
+
+func TestDecimalResult(t *testing.T) {
+	client, db := testClient(func(string) string {
+		return `[{"id": "u1", "balance": "0.30000000000000000001"}]`
+	})
+	balance := MustDecimal("0.30000000000000000001")
+	users, err := client.User.FindMany((&UserWhere{}).BalanceGt(balance))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(db.sent()[0], `balance_gt: "0.30000000000000000001"`) {
+		t.Fatalf("expected the exact decimal in %s", db.sent()[0])
+	}
+	if users[0].Balance != balance {
+		t.Fatalf("expected %s, got %s", balance, users[0].Balance)
+	}
+}
+
+func TestDecimalNull(t *testing.T) {
+	d := MustDecimal("1.5")
+	if err := json.Unmarshal([]byte("null"), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.String() != "0" {
+		t.Fatalf("expected null to decode to 0, got %s", d)
+	}
+	var n NullDecimal
+	if err := json.Unmarshal([]byte(`"1.50"`), &n); err != nil {
+		t.Fatal(err)
+	}
+	if !n.Valid || n.Decimal.String() != "1.50" {
+		t.Fatalf("expected 1.50, got %+v", n)
+	}
+}
+
+func TestBigIntNull(t *testing.T) {
+	b := BigInt(7)
+	if err := json.Unmarshal([]byte("null"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if b != 0 {
+		t.Fatalf("expected null to decode to 0, got %d", b)
+	}
+	var n NullBigInt
+	if err := json.Unmarshal([]byte("null"), &n); err != nil || n.Valid {
+		t.Fatalf("expected a null BigInt, got %+v, %v", n, err)
+	}
+}
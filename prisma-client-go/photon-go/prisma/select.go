@@ -0,0 +1,316 @@
+package prisma
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// schemaModel describes a model's fields and relations for Select
+type schemaModel struct {
+	name      string
+	record    reflect.Type
+	relations reflect.Type
+}
+
+var (
+	userSchema    = &schemaModel{"User", reflect.TypeOf(User{}), reflect.TypeOf(UserRelations{})}
+	postSchema    = &schemaModel{"Post", reflect.TypeOf(Post{}), reflect.TypeOf(PostRelations{})}
+	commentSchema = &schemaModel{"Comment", reflect.TypeOf(Comment{}), reflect.TypeOf(CommentRelations{})}
+)
+
+// schemaOf returns the model of a record type
+func schemaOf(t reflect.Type) *schemaModel {
+	for _, m := range []*schemaModel{userSchema, postSchema, commentSchema} {
+		if m.record == t {
+			return m
+		}
+	}
+	return nil
+}
+
+// live are the arguments that leave out the deleted records of a soft
+// deletable model, for relations included without conditions
+func (m *schemaModel) live() object {
+	r := modelRules[m.name]
+	if r.deletedAt == "" {
+		return nil
+	}
+	return whereArgs(nil, excludeDeleted.filter(r.deletedAt))
+}
+
+// jsonName is the name of a field in the engine's results
+func jsonName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" || f.PkgPath != "" {
+		return ""
+	}
+	return name
+}
+
+// scalar returns the scalar field of the model with the given Go name
+func (m *schemaModel) scalar(name string) (reflect.StructField, bool) {
+	f, ok := m.record.FieldByName(name)
+	return f, ok && jsonName(f) != ""
+}
+
+// relation returns the relation of the model with the given Go name
+func (m *schemaModel) relation(name string) (reflect.StructField, bool) {
+	f, ok := m.relations.FieldByName(name)
+	return f, ok && jsonName(f) != ""
+}
+
+// selectField maps a field of a Select struct to a field of a model
+type selectField struct {
+	index []int  // index of the field within the Select struct
+	key   string // key of the field in the result
+	// typ is the type of the model's field, which values are decoded into
+	// before being converted to the field's own type
+	typ reflect.Type
+	// relation is set for related records, count for relation counts
+	relation *selectPlan
+	args     object
+	count    string
+}
+
+// selectPlan maps a Select struct to the fields of a model
+type selectPlan struct {
+	fields []*selectField
+}
+
+// planSelect maps the fields of t to the fields of m. Fields are matched by
+// name, embedded structs that aren't fields of m are flattened, and fields
+// named after a to-many relation followed by Count hold the number of
+// related records. c holds the conditions of the records being selected,
+// which include the conditions of their relations.
+func planSelect(m *schemaModel, t reflect.Type, c *conditions, index []int) (*selectPlan, error) {
+	plan := &selectPlan{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		path := append(append([]int{}, index...), i)
+		if f, ok := m.scalar(sf.Name); ok {
+			if !f.Type.ConvertibleTo(sf.Type) {
+				return nil, fmt.Errorf("prisma: %s.%s is a %s, which can't be stored in a %s", m.name, f.Name, f.Type, sf.Type)
+			}
+			plan.fields = append(plan.fields, &selectField{index: path, key: jsonName(f), typ: f.Type})
+			continue
+		}
+		if f, ok := m.relation(sf.Name); ok {
+			field, err := planRelation(f, sf, c)
+			if err != nil {
+				return nil, err
+			}
+			field.index = path
+			plan.fields = append(plan.fields, field)
+			continue
+		}
+		if f, ok := m.relation(strings.TrimSuffix(sf.Name, "Count")); ok && f.Type.Kind() == reflect.Slice {
+			switch sf.Type.Kind() {
+			case reflect.Int, reflect.Int32, reflect.Int64:
+			default:
+				return nil, fmt.Errorf("prisma: %s must be an integer to count the %s", sf.Name, jsonName(f))
+			}
+			field := &selectField{index: path, key: sf.Name, count: jsonName(f)}
+			field.args = schemaOf(f.Type.Elem().Elem()).live()
+			for _, count := range c.counts {
+				if count.relation == field.count {
+					field.args = count.args
+				}
+			}
+			plan.fields = append(plan.fields, field)
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			embedded, err := planSelect(m, sf.Type, c, path)
+			if err != nil {
+				return nil, err
+			}
+			plan.fields = append(plan.fields, embedded.fields...)
+			continue
+		}
+		return nil, fmt.Errorf("prisma: %s has no field %s", m.name, sf.Name)
+	}
+	return plan, nil
+}
+
+// planRelation plans a Select field holding related records
+func planRelation(f, sf reflect.StructField, c *conditions) (*selectField, error) {
+	target := f.Type
+	if target.Kind() == reflect.Slice {
+		target = target.Elem()
+	}
+	related := schemaOf(target.Elem())
+	t := sf.Type
+	if f.Type.Kind() == reflect.Slice {
+		if t.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("prisma: %s must be a slice", sf.Name)
+		}
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("prisma: %s must hold structs", sf.Name)
+	}
+	field := &selectField{key: jsonName(f)}
+	if f.Type.Kind() == reflect.Slice {
+		field.args = related.live()
+	}
+	nested := &conditions{}
+	for _, with := range c.with {
+		if with.relation == field.key {
+			field.args = with.args
+			if with.nested != nil {
+				nested = with.nested
+			}
+		}
+	}
+	plan, err := planSelect(related, t, nested, nil)
+	if err != nil {
+		return nil, err
+	}
+	field.relation = plan
+	return field, nil
+}
+
+// selection compiles the plan into the fields to select and the relations
+// to select along with them. Counts are aliased by their field name, so
+// several counts can be selected at once.
+func (p *selectPlan) selection() (string, []*related) {
+	var b strings.Builder
+	var list []*related
+	seen := map[string]bool{}
+	for _, f := range p.fields {
+		if seen[f.key] {
+			continue
+		}
+		seen[f.key] = true
+		switch {
+		case f.count != "":
+			list = append(list, &related{key: f.key, relation: f.count, args: f.args, count: true})
+		case f.relation != nil:
+			r := &related{key: f.key, relation: f.key, args: f.args}
+			r.fields, r.related = f.relation.selection()
+			list = append(list, r)
+		default:
+			if b.Len() > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(f.key)
+		}
+	}
+	return b.String(), list
+}
+
+// decode a record into v, a Select struct
+func (p *selectPlan) decode(data json.RawMessage, v reflect.Value) error {
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+	for _, f := range p.fields {
+		raw, ok := record[f.key]
+		if !ok {
+			continue
+		}
+		dst := v.FieldByIndex(f.index)
+		switch {
+		case f.count != "":
+			var counts map[string]int64
+			if err := json.Unmarshal(raw, &counts); err != nil {
+				return err
+			}
+			dst.SetInt(counts[f.count])
+		case f.relation != nil:
+			if err := f.relation.decodeRelation(raw, dst); err != nil {
+				return err
+			}
+		default:
+			value := reflect.New(f.typ)
+			if err := json.Unmarshal(raw, value.Interface()); err != nil {
+				return err
+			}
+			dst.Set(value.Elem().Convert(dst.Type()))
+		}
+	}
+	return nil
+}
+
+// decodeRelation decodes related records into a slice, a pointer or a
+// struct. A null to-one relation leaves v untouched.
+func (p *selectPlan) decodeRelation(data json.RawMessage, v reflect.Value) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if v.Kind() == reflect.Slice {
+		var records []json.RawMessage
+		if err := json.Unmarshal(data, &records); err != nil {
+			return err
+		}
+		return p.decodeRecords(records, v)
+	}
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	return p.decode(data, v)
+}
+
+// decodeRecords decodes records into a slice
+func (p *selectPlan) decodeRecords(records []json.RawMessage, v reflect.Value) error {
+	slice := reflect.MakeSlice(v.Type(), len(records), len(records))
+	for i, record := range records {
+		if err := p.decodeRelation(record, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+	v.Set(slice)
+	return nil
+}
+
+// selectInto finds the records matching c and decodes them into v, which
+// points to a Select struct or a slice of them. A struct is filled in with
+// the first match and fails with ErrNotFound when nothing matches.
+func (c *Client) selectInto(m *schemaModel, scope object, cond *conditions, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("prisma: Select needs a pointer, not %T", v)
+	}
+	rv = rv.Elem()
+	t := rv.Type()
+	many := t.Kind() == reflect.Slice
+	if many {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	} else if cond.first == nil && cond.last == nil {
+		one := 1
+		cond.first = &one
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("prisma: Select needs a struct or a slice of structs, not %T", v)
+	}
+	plan, err := planSelect(m, t, cond, nil)
+	if err != nil {
+		return err
+	}
+	op := &operation{
+		name: "findMany" + m.name,
+		args: cond.args(scope),
+	}
+	op.selection, op.related = plan.selection()
+	var records []json.RawMessage
+	if err := c.send(op, &records); err != nil {
+		return err
+	}
+	if many {
+		return plan.decodeRecords(records, rv)
+	}
+	if len(records) == 0 {
+		return ErrNotFound
+	}
+	return plan.decode(records[0], rv)
+}
@@ -0,0 +1,220 @@
+package prisma_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prisma/specs/photongo/photon-go/prisma"
+	"github.com/prisma/specs/photongo/photon-go/prisma/comment"
+	"github.com/prisma/specs/photongo/photon-go/prisma/post"
+	"github.com/prisma/specs/photongo/photon-go/prisma/user"
+)
+
+type listedPost struct {
+	post.ID
+	post.Title
+	post.CommentsCount
+}
+
+type authoredPost struct {
+	ID     string
+	Author *struct {
+		Email string
+	}
+}
+
+type postAndUnknown struct {
+	post.ID
+	Rating int
+}
+
+type postWithIntTitle struct {
+	Title int
+}
+
+type postWithStringCount struct {
+	CommentsCount string
+}
+
+type userWithPosts struct {
+	user.ID
+	user.Email
+	Posts []struct {
+		post.Title
+		Comments []struct {
+			comment.Comment
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name string
+		// selectInto selects into a new value of the test's type
+		into       func(client *prisma.Client) (interface{}, error)
+		result     string
+		selection  string
+		want       interface{}
+		err        string
+		notFound   bool
+		unanswered bool
+	}{
+		{
+			name: "embedded generated fields and counts",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var posts []listedPost
+				err := client.Post.Select(&posts)
+				return posts, err
+			},
+			result:    `[{"id": "p1", "title": "a", "CommentsCount": {"comments": 3}}]`,
+			selection: `findManyPost(where: {deletedAt: null}) { id title CommentsCount: _count { comments } }`,
+			want:      []listedPost{{ID: "p1", Title: "a", CommentsCount: 3}},
+		},
+		{
+			name: "filtered counts",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var posts []listedPost
+				err := client.Post.Select(&posts, post.WithCommentsCount(comment.Where().Email("ada@prisma.io")))
+				return posts, err
+			},
+			result:    `[{"id": "p1", "title": "a", "CommentsCount": {"comments": 1}}]`,
+			selection: `CommentsCount: _count { comments(where: {writtenBy: {email: "ada@prisma.io"}}) }`,
+			want:      []listedPost{{ID: "p1", Title: "a", CommentsCount: 1}},
+		},
+		{
+			name: "slice of pointers",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var posts []*listedPost
+				err := client.Post.Select(&posts)
+				return posts, err
+			},
+			result: `[{"id": "p1", "title": "a", "CommentsCount": {"comments": 0}}, {"id": "p2", "title": "b", "CommentsCount": {"comments": 2}}]`,
+			want:   []*listedPost{{ID: "p1", Title: "a"}, {ID: "p2", Title: "b", CommentsCount: 2}},
+		},
+		{
+			name: "struct takes the first match",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var p listedPost
+				err := client.Post.Select(&p)
+				return p, err
+			},
+			result:    `[{"id": "p1", "title": "a", "CommentsCount": {"comments": 3}}]`,
+			selection: `findManyPost(where: {deletedAt: null}, first: 1)`,
+			want:      listedPost{ID: "p1", Title: "a", CommentsCount: 3},
+		},
+		{
+			name: "struct without a match",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var p listedPost
+				err := client.Post.Select(&p)
+				return nil, err
+			},
+			result:   `[]`,
+			notFound: true,
+		},
+		{
+			name: "to-one relation into a pointer",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var posts []authoredPost
+				err := client.Post.Select(&posts)
+				return posts, err
+			},
+			result:    `[{"id": "p1", "author": {"email": "ada@prisma.io"}}, {"id": "p2", "author": null}]`,
+			selection: `{ id author { email } }`,
+			want: []authoredPost{
+				{ID: "p1", Author: &struct{ Email string }{"ada@prisma.io"}},
+				{ID: "p2"},
+			},
+		},
+		{
+			name: "nested relations and embedded models",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var u userWithPosts
+				err := client.User.Select(&u, user.WithPosts(post.First(2)))
+				return u.Posts[0].Comments[0].Text, err
+			},
+			result:    `[{"id": "u1", "email": "ada@prisma.io", "posts": [{"title": "a", "comments": [{"text": "hi"}]}]}]`,
+			selection: `{ id email posts(where: {deletedAt: null}, first: 2) { title comments { id createdAt text } } }`,
+			want:      comment.Text("hi"),
+		},
+		{
+			name: "unknown field",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var posts []postAndUnknown
+				return nil, client.Post.Select(&posts)
+			},
+			err:        "prisma: Post has no field Rating",
+			unanswered: true,
+		},
+		{
+			name: "mismatched type",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var posts []postWithIntTitle
+				return nil, client.Post.Select(&posts)
+			},
+			err:        "prisma: Post.Title is a string, which can't be stored in a int",
+			unanswered: true,
+		},
+		{
+			name: "count into a string",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var posts []postWithStringCount
+				return nil, client.Post.Select(&posts)
+			},
+			err:        "prisma: CommentsCount must be an integer to count the comments",
+			unanswered: true,
+		},
+		{
+			name: "not a pointer",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var posts []listedPost
+				return nil, client.Post.Select(posts)
+			},
+			err:        "prisma: Select needs a pointer, not []prisma_test.listedPost",
+			unanswered: true,
+		},
+		{
+			name: "not a struct",
+			into: func(client *prisma.Client) (interface{}, error) {
+				var ids []string
+				return nil, client.Post.Select(&ids)
+			},
+			err:        "prisma: Select needs a struct or a slice of structs, not *[]string",
+			unanswered: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.result
+			e := &engine{respond: func(string) string { return result }}
+			server := httptest.NewServer(e)
+			defer server.Close()
+			got, err := test.into(prisma.New(server.URL))
+			switch {
+			case test.notFound:
+				if err != prisma.ErrNotFound {
+					t.Fatalf("expected ErrNotFound, got %v", err)
+				}
+				return
+			case test.err != "":
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("expected %q, got %v", test.err, err)
+				}
+				if test.unanswered && len(e.queries) != 0 {
+					t.Fatalf("expected nothing to be sent, got %v", e.queries)
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+			if test.selection != "" && !strings.Contains(e.last(), test.selection) {
+				t.Fatalf("expected %s in %s", test.selection, e.last())
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package prisma
+
+// deletedScope is whether reads of a soft deletable model match the
+// records that were soft deleted
+type deletedScope int
+
+const (
+	// excludeDeleted leaves out the deleted records, which is the default
+	excludeDeleted deletedScope = iota
+	// withDeleted matches every record
+	withDeleted
+	// onlyDeleted only matches the deleted records
+	onlyDeleted
+)
+
+// filter compiles the scope into a filter on the model's @deletedAt field
+func (d deletedScope) filter(field string) object {
+	switch d {
+	case withDeleted:
+		return nil
+	case onlyDeleted:
+		return object{{field + "_not", nil}}
+	default:
+		return object{{field, nil}}
+	}
+}
+
+// softDelete sets the @deletedAt field of the unique record instead of
+// deleting it. A record that's already deleted isn't found.
+func (c *Client) softDelete(r *rules, field string, unique, cond object) error {
+	now := c.now()
+	data := r.stamp(object{{field, now}}, false, now)
+	return c.updateIf(r.model, data, unique, cond)
+}
+
+// softDeleteMany sets the @deletedAt field of the records matching where
+// that aren't deleted yet
+func (c *Client) softDeleteMany(r *rules, field string, where object) (*BatchPayload, error) {
+	now := c.now()
+	op := &operation{
+		mutation:  true,
+		name:      "updateMany" + r.model,
+		args:      whereArgs(and(where, excludeDeleted.filter(field)), nil).set("data", r.stamp(object{{field, now}}, false, now)),
+		selection: "count",
+	}
+	var result BatchPayload
+	if err := c.send(op, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,152 @@
+package prisma
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// deletedPost answers the queries of a soft delete of p1
+func deletedPost(query string) string {
+	if strings.Contains(query, "updateManyPost") {
+		return `{"count": 1}`
+	}
+	return `[{"id": "p1", "deletedAt": "2020-01-02T03:04:05Z"}]`
+}
+
+func TestSoftDelete(t *testing.T) {
+	client, db := testClient(deletedPost)
+	client = client.WithClock(ClockFunc(func() time.Time { return fixed }))
+	p, err := client.Post.Delete((&PostWhere{}).ID("p1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.DeletedAt.Valid || !p.DeletedAt.Time.Equal(fixed) {
+		t.Fatalf("expected the deleted post, got %+v", p)
+	}
+	sent := db.sent()
+	if len(sent) != 2 {
+		t.Fatalf("expected an update and a read, got %v", sent)
+	}
+	// the post is updated instead of deleted, unless it already was
+	for _, want := range []string{
+		`updateManyPost(where: {AND: [{id: "p1"}, {deletedAt: null}]}`,
+		`data: {deletedAt: "2020-01-02T03:04:05Z", updatedAt: "2020-01-02T03:04:05Z"}`,
+	} {
+		if !strings.Contains(sent[0], want) {
+			t.Fatalf("expected %s in %s", want, sent[0])
+		}
+	}
+	if !strings.Contains(sent[1], `findManyPost(where: {AND: [{deletedAt_not: null}, {id: "p1"}]}`) {
+		t.Fatalf("expected the deleted post to be read back in %s", sent[1])
+	}
+}
+
+func TestSoftDeleteNotFound(t *testing.T) {
+	// deleting a deleted post is like deleting a missing one
+	client, _ := testClient(func(string) string { return `{"count": 0}` })
+	if _, err := client.Post.Delete((&PostWhere{}).ID("p1")); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSoftDeleteMany(t *testing.T) {
+	client, db := testClient(func(string) string { return `{"count": 2}` })
+	result, err := client.Post.DeleteMany((&PostWhere{}).Published(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Count != 2 {
+		t.Fatalf("expected 2 posts to be deleted, got %d", result.Count)
+	}
+	query := db.sent()[0]
+	if !strings.Contains(query, `updateManyPost(where: {AND: [{published: false}, {deletedAt: null}]}`) {
+		t.Fatalf("expected the posts that aren't deleted to be updated in %s", query)
+	}
+}
+
+func TestSoftDeleteReads(t *testing.T) {
+	tests := []struct {
+		name  string
+		where *PostWhere
+		want  string
+	}{
+		{"default", (&PostWhere{}).Published(true), `findManyPost(where: {AND: [{deletedAt: null}, {published: true}]})`},
+		{"with deleted", (&PostWhere{}).Published(true).WithDeleted(), `findManyPost(where: {published: true})`},
+		{"only deleted", (&PostWhere{}).Published(true).OnlyDeleted(), `findManyPost(where: {AND: [{deletedAt_not: null}, {published: true}]})`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, db := testClient(func(string) string { return `[]` })
+			if _, err := client.Post.FindMany(test.where); err != nil {
+				t.Fatal(err)
+			}
+			if query := db.sent()[0]; !strings.Contains(query, test.want) {
+				t.Fatalf("expected %s in %s", test.want, query)
+			}
+		})
+	}
+	// related posts are filtered too
+	client, db := testClient(func(string) string { return `[]` })
+	if _, err := client.User.FindMany((&UserWith{}).Posts()); err != nil {
+		t.Fatal(err)
+	}
+	if query := db.sent()[0]; !strings.Contains(query, `posts(where: {deletedAt: null})`) {
+		t.Fatalf("expected the deleted posts to be left out in %s", query)
+	}
+}
+
+func TestSoftDeleteCount(t *testing.T) {
+	client, db := testClient(func(string) string { return `{"count": 1}` })
+	if _, err := client.Post.Count(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Post.Count((&PostWhere{}).OnlyDeleted()); err != nil {
+		t.Fatal(err)
+	}
+	sent := db.sent()
+	if !strings.Contains(sent[0], `aggregatePost(where: {deletedAt: null})`) || !strings.Contains(sent[1], `aggregatePost(where: {deletedAt_not: null})`) {
+		t.Fatalf("expected the counts to honor the deleted posts, got %v", sent)
+	}
+}
+
+func TestSoftDeleteTenancy(t *testing.T) {
+	client, db := tenantClient(func(query string) string {
+		if strings.Contains(query, "updateMany") {
+			return `{"count": 0}`
+		}
+		return `[]`
+	})
+	if _, err := client.Post.FindMany((&PostWhere{}).OnlyDeleted()); err != nil {
+		t.Fatal(err)
+	}
+	// the tenant's filter is added to the deleted posts' one
+	if query := db.sent()[0]; !strings.Contains(query, `findManyPost(where: {AND: [{deletedAt_not: null}, {tenantId: "t1"}]})`) {
+		t.Fatalf("expected the deleted posts of the tenant in %s", query)
+	}
+	// and so are soft deletes
+	if _, err := client.Post.DeleteMany(&PostWhere{}); err != nil {
+		t.Fatal(err)
+	}
+	if query := db.sent()[1]; !strings.Contains(query, `updateManyPost(where: {AND: [{deletedAt: null}, {tenantId: "t1"}]}`) {
+		t.Fatalf("expected the posts of the tenant to be deleted in %s", query)
+	}
+}
+
+func TestSoftDeletePolicy(t *testing.T) {
+	client, db := policyClient(func(string) string { return `[]` })
+	if _, err := client.Post.FindMany(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Post.FindMany((&PostWhere{}).WithDeleted()); err != nil {
+		t.Fatal(err)
+	}
+	sent := db.sent()
+	if !strings.Contains(sent[0], `findManyPost(where: {AND: [{deletedAt: null}, {published: true}]})`) {
+		t.Fatalf("expected the live published posts in %s", sent[0])
+	}
+	// WithDeleted doesn't lift the policy
+	if !strings.Contains(sent[1], `findManyPost(where: {published: true})`) {
+		t.Fatalf("expected the published posts in %s", sent[1])
+	}
+}
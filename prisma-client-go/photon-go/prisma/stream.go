@@ -0,0 +1,138 @@
+package prisma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Streamer is implemented by transports that can stream a query's response
+// instead of buffering it
+type Streamer interface {
+	Stream(ctx context.Context, query string) (io.ReadCloser, error)
+}
+
+// stream calls each with the records of an operation's result as they're
+// decoded. Transports that can't stream fall back to a buffered Send.
+func (c *Client) stream(ctx context.Context, op *operation, each func(json.RawMessage) error) error {
+	query, err := c.compile(ctx, op)
+	if err != nil {
+		return err
+	}
+	streamer, ok := c.db.(Streamer)
+	if !ok {
+		result, err := c.fetch(ctx, query)
+		if err != nil {
+			return err
+		}
+		var records []json.RawMessage
+		if err := json.Unmarshal(result, &records); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := each(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	r, err := streamer.Stream(ctx, query)
+	if err != nil {
+		return err
+	}
+	// closing early stops the transport from producing more records
+	defer r.Close()
+	return decodeStream(json.NewDecoder(r), each)
+}
+
+// decodeStream walks a {"data": {"result": [...]}} response one record at a
+// time
+func decodeStream(dec *json.Decoder, each func(json.RawMessage) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "data":
+			if err := decodeStreamData(dec, each); err != nil {
+				return err
+			}
+		case "errors":
+			var errs []*EngineError
+			if err := dec.Decode(&errs); err != nil {
+				return err
+			}
+			if len(errs) > 0 {
+				return errs[0]
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func decodeStreamData(dec *json.Decoder, each func(json.RawMessage) error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("prisma: unexpected %v in response", tok)
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key != "result" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok == nil {
+			continue
+		}
+		if tok != json.Delim('[') {
+			return fmt.Errorf("prisma: unexpected %v in response", tok)
+		}
+		for dec.More() {
+			var record json.RawMessage
+			if err := dec.Decode(&record); err != nil {
+				return err
+			}
+			if err := each(record); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("prisma: expected %v in response, got %v", delim, tok)
+	}
+	return nil
+}
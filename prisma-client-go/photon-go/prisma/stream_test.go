@@ -0,0 +1,100 @@
+package prisma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodeStream(t *testing.T) {
+	stop := errors.New("stop")
+	tests := []struct {
+		name    string
+		body    string
+		records []string
+		err     string
+		// stopAt makes each fail on that record
+		stopAt string
+	}{
+		{name: "records", body: `{"data": {"result": [{"id": "1"}, {"id": "2"}]}}`, records: []string{`{"id": "1"}`, `{"id": "2"}`}},
+		{name: "empty", body: `{"data": {"result": []}}`},
+		{name: "null result", body: `{"data": {"result": null}}`},
+		{name: "null data", body: `{"data": null, "errors": [{"message": "boom"}]}`, err: "prisma: boom"},
+		{name: "other keys", body: `{"extensions": {"a": [1]}, "data": {"other": 1, "result": [{"id": "1"}]}}`, records: []string{`{"id": "1"}`}},
+		{name: "errors after data", body: `{"data": {"result": [{"id": "1"}]}, "errors": [{"message": "boom", "code": "P2000"}]}`, records: []string{`{"id": "1"}`}, err: "prisma: boom"},
+		{name: "errors before data", body: `{"errors": [{"message": "boom"}], "data": {"result": [{"id": "1"}]}}`, err: "prisma: boom"},
+		{name: "truncated", body: `{"data": {"result": [{"id": "1"}, {"id"`, records: []string{`{"id": "1"}`}, err: "unexpected EOF"},
+		{name: "not an object", body: `[]`, err: "prisma: expected { in response, got ["},
+		{name: "result not a list", body: `{"data": {"result": {"id": "1"}}}`, err: "prisma: unexpected { in response"},
+		{name: "each fails", body: `{"data": {"result": [{"id": "1"}, {"id": "2"}, {"id": "3"}]}}`, records: []string{`{"id": "1"}`, `{"id": "2"}`}, stopAt: `{"id": "2"}`, err: "stop"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var records []string
+			err := decodeStream(json.NewDecoder(strings.NewReader(test.body)), func(record json.RawMessage) error {
+				records = append(records, string(record))
+				if string(record) == test.stopAt {
+					return stop
+				}
+				return nil
+			})
+			if got := fmt.Sprint(err); (err == nil) != (test.err == "") || (err != nil && got != test.err) {
+				t.Fatalf("expected error %q, got %v", test.err, err)
+			}
+			if strings.Join(records, ",") != strings.Join(test.records, ",") {
+				t.Fatalf("expected records %v, got %v", test.records, records)
+			}
+		})
+	}
+}
+
+func TestDecodeStreamEngineError(t *testing.T) {
+	body := `{"data": {"result": [{"id": "1"}]}, "errors": [{"message": "boom", "code": "P2000"}]}`
+	err := decodeStream(json.NewDecoder(strings.NewReader(body)), func(json.RawMessage) error { return nil })
+	e, ok := err.(*EngineError)
+	if !ok || e.Code != "P2000" {
+		t.Fatalf("expected the engine's error, got %#v", err)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "<html>bad gateway</html>\n")
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	want := "prisma: engine returned 502 Bad Gateway: <html>bad gateway</html>"
+	if _, err := client.User.FindMany(); err == nil || err.Error() != want {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+	err := client.User.FindEach(context.Background(), func(*User) error { return nil })
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+}
+
+func TestHTTPStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"result": [{"id": "u1"}, {"id": "u2"}]}}`)
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	var ids []string
+	err := client.User.FindEach(context.Background(), func(u *User) error {
+		ids = append(ids, u.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, " ") != "u1 u2" {
+		t.Fatalf("expected u1 and u2, got %v", ids)
+	}
+}
@@ -0,0 +1,222 @@
+package prisma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoTenant is returned by queries on models scoped by a Tenancy when
+// their context has no tenant. Nothing is sent to the engine.
+var ErrNoTenant = errors.New("prisma: no tenant in the context")
+
+// Tenancy scopes the queries of models to the tenant of their context
+type Tenancy struct {
+	// Fields maps each scoped model to its tenant field, like
+	// {"Post": "tenantId"}. Other models aren't scoped.
+	Fields map[string]string
+	// Tenant reads the tenant from a context, TenantFrom when nil
+	Tenant func(ctx context.Context) (string, bool)
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx whose queries are scoped to tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom returns the tenant set by WithTenant
+func TenantFrom(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// WithTenancy returns a copy of the client that scopes every query on the
+// models of t.Fields to the tenant of the query's context. Reads, updates
+// and deletes only match the tenant's records, including through relation
+// filters and As. Relations included with With or counted by Select, and
+// the relation counts records are ordered by, only hold the tenant's
+// records. Creates, nested creates included, are assigned to the tenant
+// and nested connects only find the tenant's records. Single record writes
+// and connects count their record within the tenant before they're sent
+// and fail with ErrNotFound when it's not found.
+func (c *Client) WithTenancy(t Tenancy) *Client {
+	if t.Tenant == nil {
+		t.Tenant = TenantFrom
+	}
+	client := *c
+	client.tenancy = &t
+	return client.bind()
+}
+
+// compile an operation into a query, restricted by the policies of the
+// models it reaches and scoped to the tenant of ctx
+func (c *Client) compile(ctx context.Context, op *operation) (string, error) {
+	op, err := c.policies.restrict(ctx, op)
+	if err != nil {
+		return "", err
+	}
+	if c.tenancy != nil {
+		if op, err = c.tenancy.scope(ctx, op); err != nil {
+			return "", err
+		}
+	}
+	return op.String(), nil
+}
+
+// operationKind is the kind of an operation, the prefix of its name
+type operationKind struct {
+	prefix string
+	// unique operations write a single record and take a unique where
+	unique bool
+}
+
+// create operations don't filter records
+func (k operationKind) create() bool {
+	return strings.HasPrefix(k.prefix, "create")
+}
+
+// operations are the kinds of operations, the rest of their name is the
+// model
+var operations = []operationKind{
+	{"findMany", false},
+	{"aggregate", false},
+	{"groupBy", false},
+	{"createOne", false},
+	{"createMany", false},
+	{"updateOne", true},
+	{"updateMany", false},
+	{"deleteOne", true},
+	{"deleteMany", false},
+	{"upsertOne", true},
+}
+
+// splitOperation splits the name of an operation into its kind and model
+func splitOperation(name string) (kind operationKind, model string, ok bool) {
+	for _, kind := range operations {
+		if strings.HasPrefix(name, kind.prefix) {
+			return kind, strings.TrimPrefix(name, kind.prefix), true
+		}
+	}
+	return kind, "", false
+}
+
+// tenantScope scopes a single operation
+type tenantScope struct {
+	*Tenancy
+	ctx    context.Context
+	tenant string
+}
+
+// scope rewrites op so it only matches and writes the records of the
+// tenant of ctx
+func (t *Tenancy) scope(ctx context.Context, op *operation) (*operation, error) {
+	kind, model, ok := splitOperation(op.name)
+	if !ok {
+		return op, nil
+	}
+	s := &tenantScope{Tenancy: t, ctx: ctx}
+	out, err := restrict(s, op)
+	if err != nil {
+		return nil, err
+	}
+	args, err := s.args(model, kind, out.args)
+	if err != nil {
+		return nil, err
+	}
+	out.args = args
+	return out, nil
+}
+
+// args scopes the data of a write on model
+func (s *tenantScope) args(model string, kind operationKind, args object) (object, error) {
+	out := args
+	for _, f := range args {
+		var value interface{}
+		var err error
+		switch f.name {
+		case "data":
+			value, err = s.data(model, f.value, kind.create())
+		case "create":
+			value, err = s.data(model, f.value, true)
+		case "update":
+			value, err = s.data(model, f.value, false)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = out.set(f.name, value)
+	}
+	return out, nil
+}
+
+// get the tenant of the context, failing when there's none
+func (s *tenantScope) get() (string, error) {
+	if s.tenant == "" {
+		tenant, ok := s.Tenant(s.ctx)
+		if !ok {
+			return "", ErrNoTenant
+		}
+		s.tenant = tenant
+	}
+	return s.tenant, nil
+}
+
+// filter returns the tenant filter of a model, nil for models that aren't
+// scoped
+func (s *tenantScope) filter(model string) (object, error) {
+	field, ok := s.Fields[model]
+	if !ok {
+		return nil, nil
+	}
+	tenant, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	return object{{field, tenant}}, nil
+}
+
+// data scopes the data of a write on model. Creates are assigned to the
+// tenant and writes can't move a record to another tenant.
+func (s *tenantScope) data(model string, data interface{}, create bool) (interface{}, error) {
+	return each(data, func(data object) (object, error) {
+		if field, ok := s.Fields[model]; ok {
+			tenant, err := s.get()
+			if err != nil {
+				return nil, err
+			}
+			if value, ok := data.get(field); ok && value != tenant {
+				return nil, fmt.Errorf("prisma: %s.%s can't be set to another tenant", model, field)
+			}
+			if create {
+				data = data.set(field, tenant)
+			}
+		}
+		return s.nested(model, data)
+	})
+}
+
+// nested assigns the records created by the nested writes within the data
+// of a write on model to the tenant. Nested connects are checked by
+// sendVerified.
+func (s *tenantScope) nested(model string, data object) (object, error) {
+	out := data
+	for _, rule := range modelRules[model].fields {
+		value, _ := data.get(rule.field)
+		nested, _ := value.(object)
+		create, ok := nested.get("create")
+		if rule.relation == "" || !ok {
+			continue
+		}
+		value, err := s.data(rule.relation, create, true)
+		if err != nil {
+			return nil, err
+		}
+		out = out.set(rule.field, nested.set("create", value))
+	}
+	return out, nil
+}
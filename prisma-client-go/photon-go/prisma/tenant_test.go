@@ -0,0 +1,201 @@
+package prisma
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// tenantClient returns a client scoping users, posts and comments to the
+// tenant t1
+func tenantClient(respond func(query string) string) (*Client, *testDB) {
+	client, db := testClient(respond)
+	client = client.WithTenancy(Tenancy{Fields: map[string]string{
+		"User":    "tenantId",
+		"Post":    "tenantId",
+		"Comment": "tenantId",
+	}})
+	return client.WithContext(WithTenant(context.Background(), "t1")), db
+}
+
+func TestTenancyWith(t *testing.T) {
+	client, db := tenantClient(func(string) string { return `[]` })
+	with := (&UserWith{}).Posts((&PostWhere{}).Published(true), (&PostWith{}).Comments())
+	if _, err := client.User.FindMany(with); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	for _, want := range []string{
+		`findManyUser(where: {tenantId: "t1"})`,
+		`posts(where: {AND: [{AND: [{deletedAt: null}, {published: true}]}, {tenantId: "t1"}]})`,
+		`comments(where: {tenantId: "t1"})`,
+	} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("expected %s in %s", want, query)
+		}
+	}
+}
+
+func TestTenancySelectCount(t *testing.T) {
+	client, db := tenantClient(func(string) string { return `[]` })
+	var users []struct {
+		ID         string
+		PostsCount int
+	}
+	if err := client.User.Select(&users); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	if !strings.Contains(query, `PostsCount: _count { posts(where: {AND: [{deletedAt: null}, {tenantId: "t1"}]}) }`) {
+		t.Fatalf("expected the counted posts to be scoped in %s", query)
+	}
+}
+
+func TestTenancyOrderByCount(t *testing.T) {
+	client, db := tenantClient(func(string) string { return `[]` })
+	if _, err := client.User.FindMany((&UserOrder{}).PostsCount(DESC)); err != nil {
+		t.Fatal(err)
+	}
+	query := db.sent()[0]
+	if !strings.Contains(query, `{posts: {count: DESC, where: {tenantId: "t1"}}}`) {
+		t.Fatalf("expected the ordering posts to be scoped in %s", query)
+	}
+}
+
+func TestTenancyNoTenant(t *testing.T) {
+	client, db := tenantClient(nil)
+	client = client.WithContext(context.Background())
+	if _, err := client.User.FindMany((&UserWith{}).Posts()); err != ErrNoTenant {
+		t.Fatalf("expected ErrNoTenant, got %v", err)
+	}
+	if len(db.sent()) != 0 {
+		t.Fatalf("expected nothing to be sent, got %v", db.sent())
+	}
+}
+
+// counted answers counts with n and writes with a user
+func counted(n int) func(query string) string {
+	return func(query string) string {
+		switch {
+		case strings.Contains(query, "aggregate"):
+			return fmt.Sprintf(`{"count": %d}`, n)
+		case strings.Contains(query, "findMany"):
+			return `[{"id": "u1"}]`
+		}
+		return `{"id": "u1"}`
+	}
+}
+
+func TestTenancyUnique(t *testing.T) {
+	client, db := tenantClient(counted(1))
+	if _, err := client.User.Find((&UserWhere{}).ID("u1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.User.Update((&UserInput{}).Name("Ada"), (&UserWhere{}).ID("u1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.User.Delete((&UserWhere{}).ID("u1")); err != nil {
+		t.Fatal(err)
+	}
+	sent := db.sent()
+	if len(sent) != 5 {
+		t.Fatalf("expected a find, then a count before each write, got %v", sent)
+	}
+	if !strings.Contains(sent[0], `findManyUser(where: {AND: [{id: "u1"}, {tenantId: "t1"}]}, first: 1)`) {
+		t.Fatalf("expected the find to be scoped in %s", sent[0])
+	}
+	// a unique where only holds unique fields, the tenant is checked first
+	for i, write := range []string{"updateOneUser", "deleteOneUser"} {
+		count, query := sent[2*i+1], sent[2*i+2]
+		if !strings.Contains(count, `aggregateUser(where: {AND: [{id: "u1"}, {tenantId: "t1"}]}) { count }`) {
+			t.Fatalf("expected the user to be counted within the tenant in %s", count)
+		}
+		if !strings.Contains(query, write+"(") || !strings.Contains(query, `where: {id: "u1"})`) {
+			t.Fatalf("expected %s by id in %s", write, query)
+		}
+	}
+}
+
+func TestTenancyUniqueNotFound(t *testing.T) {
+	client, db := tenantClient(counted(0))
+	if _, err := client.Comment.Update((&CommentInput{}).Text("hi"), (&CommentWhere{}).ID("c1")); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := client.Comment.Delete((&CommentWhere{}).ID("c1")); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	for _, query := range db.sent() {
+		if !strings.Contains(query, "aggregateComment") {
+			t.Fatalf("expected only counts to be sent, got %s", query)
+		}
+	}
+}
+
+func TestTenancyUpsert(t *testing.T) {
+	insert := (&UserInput{}).Email("ada@prisma.io")
+	update := (&UserInput{}).Name("Ada")
+	for _, test := range []struct {
+		count int
+		want  string
+	}{
+		{1, `updateOneUser(data: {name: "Ada"}, where: {email: "ada@prisma.io"})`},
+		{0, `createOneUser(data: {email: "ada@prisma.io", id: "`},
+	} {
+		client, db := tenantClient(counted(test.count))
+		if _, err := client.User.Upsert(insert, update, (&UserWhere{}).Email("ada@prisma.io")); err != nil {
+			t.Fatal(err)
+		}
+		sent := db.sent()
+		if len(sent) != 2 || !strings.Contains(sent[1], test.want) {
+			t.Fatalf("expected a count then %s, got %v", test.want, sent)
+		}
+		if test.count == 0 && !strings.Contains(sent[1], `tenantId: "t1"`) {
+			t.Fatalf("expected the user to be created within the tenant in %s", sent[1])
+		}
+	}
+}
+
+func TestTenancyConnect(t *testing.T) {
+	client, db := tenantClient(counted(1))
+	comment := (&CommentInput{}).Text("hi").
+		ConnectPost((&PostConnect{}).ID("p1")).
+		ConnectWrittenBy((&UserConnect{}).ID("u1"))
+	if _, err := client.Comment.Create(comment); err != nil {
+		t.Fatal(err)
+	}
+	sent := db.sent()
+	if len(sent) != 3 {
+		t.Fatalf("expected a count for each connect, got %v", sent)
+	}
+	if !strings.Contains(sent[0], `aggregatePost(where: {AND: [{id: "p1"}, {tenantId: "t1"}]})`) || !strings.Contains(sent[1], `aggregateUser(where: {AND: [{id: "u1"}, {tenantId: "t1"}]})`) {
+		t.Fatalf("expected the connected records to be counted within the tenant, got %v", sent)
+	}
+	if !strings.Contains(sent[2], `post: {connect: {id: "p1"}}`) || !strings.Contains(sent[2], `writtenBy: {connect: {id: "u1"}}`) {
+		t.Fatalf("expected the connects to be unchanged in %s", sent[2])
+	}
+	// a record of another tenant can't be connected
+	client, db = tenantClient(counted(0))
+	if _, err := client.Comment.Create(comment); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if len(db.sent()) != 1 {
+		t.Fatalf("expected the create not to be sent, got %v", db.sent())
+	}
+}
+
+func TestTenancyUniqueTransaction(t *testing.T) {
+	db := &testDB{respond: counted(1)}
+	client := newClient(&testTransactor{testDB: db}).WithTenancy(Tenancy{Fields: map[string]string{"User": "tenantId"}})
+	client = client.WithContext(WithTenant(context.Background(), "t1"))
+	if _, err := client.User.Update((&UserInput{}).Name("Ada"), (&UserWhere{}).ID("u1")); err != nil {
+		t.Fatal(err)
+	}
+	sent := db.sent()
+	if len(sent) != 4 || sent[0] != "BEGIN" || sent[3] != "COMMIT" {
+		t.Fatalf("expected the count and the update in a transaction, got %v", sent)
+	}
+	if !strings.HasPrefix(sent[1], "tx query { result: aggregateUser") || !strings.HasPrefix(sent[2], "tx mutation { result: updateOneUser") {
+		t.Fatalf("expected the count then the update, got %v", sent)
+	}
+}
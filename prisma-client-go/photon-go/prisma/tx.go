@@ -0,0 +1,179 @@
+package prisma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrNoTransaction is returned when locking records outside of a
+// transaction, or when the transport doesn't support transactions
+var ErrNoTransaction = errors.New("prisma: not in a transaction")
+
+// RollbackError is returned by Transaction when rolling back after an error
+// fails too, so the transaction may still be open
+type RollbackError struct {
+	Err      error // Err is why the transaction was rolled back
+	Rollback error // Rollback is why the rollback failed
+}
+
+// Error implements error
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("%s (rollback failed: %s)", e.Err, e.Rollback)
+}
+
+// Unwrap returns the error that caused the rollback
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// Transactor is implemented by transports that support transactions
+type Transactor interface {
+	Begin(ctx context.Context) (Tx, error)
+}
+
+// Tx is a transport whose queries run within a transaction
+type Tx interface {
+	DB
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// Transaction calls fn with a client whose queries run within a single
+// transaction. The transaction is committed when fn returns nil and rolled
+// back when it returns an error or panics. When the rollback fails too, the
+// error, or the panic, is a *RollbackError wrapping both. Calling
+// Transaction on a client that's already in a transaction runs fn within
+// that transaction.
+func (c *Client) Transaction(ctx context.Context, fn func(tx *Client) error) (err error) {
+	if c.tx {
+		return fn(c)
+	}
+	transactor, ok := c.db.(Transactor)
+	if !ok {
+		return ErrNoTransaction
+	}
+	db, err := transactor.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	client := *c
+	client.db = db
+	client.tx = true
+	client.ctx = ctx
+	client.cache = newCache()
+	defer func() {
+		if p := recover(); p != nil {
+			if rerr := db.Rollback(ctx); rerr != nil {
+				panic(&RollbackError{Err: fmt.Errorf("panic: %v", p), Rollback: rerr})
+			}
+			panic(p)
+		}
+	}()
+	if err := fn(client.bind()); err != nil {
+		if rerr := db.Rollback(ctx); rerr != nil {
+			return &RollbackError{Err: err, Rollback: rerr}
+		}
+		return err
+	}
+	return db.Commit(ctx)
+}
+
+// lockMode is how found records are locked
+type lockMode string
+
+func (m lockMode) enum() string {
+	return string(m)
+}
+
+const (
+	// lockUpdate locks records against updates and other locks
+	lockUpdate lockMode = "UPDATE"
+	// lockShare locks records against updates, but not other shared locks
+	lockShare lockMode = "SHARE"
+)
+
+// lockWait is what to do about records that are already locked
+type lockWait string
+
+func (w lockWait) enum() string {
+	return string(w)
+}
+
+const (
+	// lockWaitDefault waits for locked records to be released
+	lockWaitDefault lockWait = ""
+	// lockSkipLocked leaves out locked records
+	lockSkipLocked lockWait = "SKIP_LOCKED"
+	// lockNoWait fails when a record is locked
+	lockNoWait lockWait = "NO_WAIT"
+)
+
+// lock adds the lock argument to a find operation
+func (c *Client) lock(op *operation, mode lockMode, wait lockWait) (*operation, error) {
+	if !c.tx {
+		return nil, ErrNoTransaction
+	}
+	lock := object{{"mode", mode}}
+	if wait != lockWaitDefault {
+		lock = lock.set("wait", wait)
+	}
+	op.args = op.args.set("lock", lock)
+	return op, nil
+}
+
+var _ Transactor = (*HTTP)(nil)
+
+// Begin a transaction. The queries of the returned transport are sent with
+// the transaction's id.
+func (c *HTTP) Begin(ctx context.Context) (Tx, error) {
+	var tx struct {
+		ID string `json:"id"`
+	}
+	if err := c.post(ctx, "/transaction/start", &tx); err != nil {
+		return nil, err
+	}
+	return &httpTx{&HTTP{URL: c.URL, Debug: c.Debug, tx: tx.ID}}, nil
+}
+
+// post to one of the engine's endpoints besides the query endpoint
+func (c *HTTP) post(ctx context.Context, path string, result interface{}) error {
+	url := strings.TrimSuffix(c.URL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader("{}"))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("prisma: %s returned %s", path, res.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// httpTx is an HTTP transport within a transaction
+type httpTx struct {
+	*HTTP
+}
+
+var _ Tx = (*httpTx)(nil)
+
+// Commit the transaction
+func (t *httpTx) Commit(ctx context.Context) error {
+	return t.post(ctx, "/transaction/"+t.tx+"/commit", nil)
+}
+
+// Rollback the transaction
+func (t *httpTx) Rollback(ctx context.Context) error {
+	return t.post(ctx, "/transaction/"+t.tx+"/rollback", nil)
+}
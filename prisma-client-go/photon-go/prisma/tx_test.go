@@ -0,0 +1,78 @@
+package prisma
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// testTransactor begins transactions on a testDB, failing their rollbacks
+// with rollbackErr. It records the transactions in the queries of the
+// testDB as BEGIN, COMMIT and ROLLBACK, and the queries sent within them
+// are prefixed with tx.
+type testTransactor struct {
+	*testDB
+	rollbackErr error
+}
+
+func (t *testTransactor) Begin(ctx context.Context) (Tx, error) {
+	t.record("BEGIN")
+	return &testTx{t}, nil
+}
+
+type testTx struct {
+	*testTransactor
+}
+
+func (t *testTx) Send(ctx context.Context, query string, result interface{}) error {
+	return t.testDB.Send(ctx, "tx "+query, result)
+}
+
+func (t *testTx) Commit(ctx context.Context) error {
+	t.record("COMMIT")
+	return nil
+}
+
+func (t *testTx) Rollback(ctx context.Context) error {
+	t.record("ROLLBACK")
+	return t.rollbackErr
+}
+
+func TestTransactionRollbackError(t *testing.T) {
+	failed := errors.New("connection reset")
+	client := newClient(&testTransactor{testDB: &testDB{}, rollbackErr: failed})
+	err := client.Transaction(context.Background(), func(tx *Client) error {
+		return ErrConflict
+	})
+	var rerr *RollbackError
+	if !errors.As(err, &rerr) || rerr.Rollback != failed {
+		t.Fatalf("expected a RollbackError, got %v", err)
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected the error to wrap ErrConflict, got %v", err)
+	}
+}
+
+func TestTransactionRollback(t *testing.T) {
+	client := newClient(&testTransactor{testDB: &testDB{}})
+	err := client.Transaction(context.Background(), func(tx *Client) error {
+		return ErrConflict
+	})
+	if err != ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestTransactionPanicRollbackError(t *testing.T) {
+	failed := errors.New("connection reset")
+	client := newClient(&testTransactor{testDB: &testDB{}, rollbackErr: failed})
+	defer func() {
+		rerr, ok := recover().(*RollbackError)
+		if !ok || rerr.Rollback != failed {
+			t.Fatalf("expected to panic with a RollbackError, got %v", rerr)
+		}
+	}()
+	client.Transaction(context.Background(), func(tx *Client) error {
+		panic("boom")
+	})
+}
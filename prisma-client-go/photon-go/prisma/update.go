@@ -0,0 +1,60 @@
+package prisma
+
+// versioned bumps the @version field of the records being updated, unless
+// data sets it
+func (r *rules) versioned(data object) object {
+	if r.version == "" {
+		return data
+	}
+	if _, ok := data.get(r.version); ok {
+		return data
+	}
+	return data.set(r.version, object{{"increment", 1}})
+}
+
+// atomically calls fn within a transaction when the transport supports
+// them, so its queries can't interleave with concurrent writes. Otherwise
+// fn's queries are sent one by one.
+func (c *Client) atomically(fn func(c *Client) error) error {
+	if _, ok := c.db.(Transactor); ok || c.tx {
+		return c.Transaction(c.Context(), fn)
+	}
+	return fn(c)
+}
+
+// updateIf updates the unique record only while cond still holds. The check
+// and the write are a single operation, so a concurrent update either
+// happens before and makes this one fail with ErrConflict, or after. A soft
+// deleted record isn't found. Telling ErrNotFound from ErrConflict takes
+// another query, so callers run it atomically.
+func (c *Client) updateIf(model string, data, unique, cond object) error {
+	if field := modelRules[model].deletedAt; field != "" {
+		unique = and(unique, excludeDeleted.filter(field))
+	}
+	op := &operation{
+		mutation:  true,
+		name:      "updateMany" + model,
+		args:      object{{"where", and(unique, cond)}, {"data", data}},
+		selection: "count",
+	}
+	var result BatchPayload
+	if err := c.send(op, &result); err != nil {
+		return err
+	}
+	if result.Count > 0 {
+		return nil
+	}
+	// nothing was updated, tell apart a missing record from a changed one
+	count := &operation{
+		name:      "aggregate" + model,
+		args:      whereArgs(nil, unique),
+		selection: "count",
+	}
+	if err := c.send(count, &result); err != nil {
+		return err
+	}
+	if result.Count == 0 {
+		return ErrNotFound
+	}
+	return ErrConflict
+}
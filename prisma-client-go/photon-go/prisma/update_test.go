@@ -0,0 +1,93 @@
+package prisma
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpdateBumpsVersion(t *testing.T) {
+	client, db := testClient(func(string) string { return `{"id": "p1"}` })
+	if _, err := client.Post.Update((&PostInput{}).Title("a"), (&PostWhere{}).ID("p1")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(db.sent()[0], "version: {increment: 1}") {
+		t.Fatalf("expected the version to be bumped in %s", db.sent()[0])
+	}
+	// a version set by the input is kept
+	if _, err := client.Post.Update(&PostInput{data: object{{"version", 5}}}, (&PostWhere{}).ID("p1")); err != nil {
+		t.Fatal(err)
+	}
+	if query := db.sent()[1]; !strings.Contains(query, "version: 5") || strings.Contains(query, "increment") {
+		t.Fatalf("expected the version to be set in %s", query)
+	}
+}
+
+func TestVersionedRules(t *testing.T) {
+	// any model is versioned by the version field of its rules
+	rules := *commentRules
+	rules.version = "revision"
+	data := rules.versioned(object{{"text", "a"}})
+	if value, _ := data.get("revision"); value == nil {
+		t.Fatalf("expected the revision to be bumped in %v", data)
+	}
+	data = rules.versioned(object{{"revision", 3}})
+	if value, _ := data.get("revision"); value != 3 {
+		t.Fatalf("expected the revision set by the data to be kept, got %v", value)
+	}
+	if data := commentRules.versioned(object{{"text", "a"}}); len(data) != 1 {
+		t.Fatalf("expected comments not to be versioned, got %v", data)
+	}
+}
+
+func TestUpdateUnversioned(t *testing.T) {
+	client, db := testClient(func(string) string { return `{"id": "u1"}` })
+	if _, err := client.User.Update((&UserInput{}).Name("a"), (&UserWhere{}).ID("u1")); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(db.sent()[0], "increment") {
+		t.Fatalf("expected no version on users in %s", db.sent()[0])
+	}
+}
+
+func TestUpdateIfTransaction(t *testing.T) {
+	db := &testDB{respond: func(query string) string {
+		if strings.Contains(query, "updateManyPost") {
+			return `{"count": 1}`
+		}
+		return `[{"id": "p1", "version": 3}]`
+	}}
+	client := newClient(&testTransactor{testDB: db})
+	post, err := client.Post.Update((&PostInput{}).Title("a"), (&PostWhere{}).ID("p1").IfVersion(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Version != 3 {
+		t.Fatalf("expected the updated post, got %+v", post)
+	}
+	// the update and the read share a transaction
+	sent := db.sent()
+	if len(sent) != 4 || sent[0] != "BEGIN" || sent[3] != "COMMIT" {
+		t.Fatalf("expected the update and the read in a transaction, got %v", sent)
+	}
+	if !strings.HasPrefix(sent[1], "tx mutation { result: updateManyPost") || !strings.HasPrefix(sent[2], "tx query { result: findManyPost") {
+		t.Fatalf("expected the update then the read, got %v", sent)
+	}
+}
+
+func TestUpdateIfSoftDeleted(t *testing.T) {
+	// the post is soft deleted, so neither query matches it
+	client, db := testClient(func(string) string { return `{"count": 0}` })
+	_, err := client.Post.Update((&PostInput{}).Title("a"), (&PostWhere{}).ID("p1").IfVersion(2))
+	if err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	sent := db.sent()
+	if len(sent) != 2 {
+		t.Fatalf("expected an update and a count, got %v", sent)
+	}
+	for _, query := range sent {
+		if !strings.Contains(query, "deletedAt: null") {
+			t.Fatalf("expected deleted posts to be excluded in %s", query)
+		}
+	}
+}
@@ -11,6 +11,12 @@ type ID string
 // Email string
 type Email string
 
+// PostsCount field, the number of posts the user wrote
+type PostsCount int
+
+// CommentsCount field, the number of comments the user wrote
+type CommentsCount int
+
 // New user input
 func New() *prisma.UserInput {
 	return &prisma.UserInput{}
@@ -18,9 +24,11 @@ func New() *prisma.UserInput {
 
 // Role enum
 var Role = struct {
+	USER  prisma.UserRole
 	ADMIN prisma.UserRole
 }{
-	ADMIN: "ADMIN",
+	USER:  prisma.UserRoleUser,
+	ADMIN: prisma.UserRoleAdmin,
 }
 
 // Connect user input
@@ -28,11 +36,49 @@ func Connect() *prisma.UserConnect {
 	return &prisma.UserConnect{}
 }
 
+// Field enum
+var Field = struct {
+	ID      prisma.UserField
+	Name    prisma.UserField
+	Email   prisma.UserField
+	Role    prisma.UserField
+	Balance prisma.UserField
+	Avatar  prisma.UserField
+}{
+	ID:      "id",
+	Name:    "name",
+	Email:   "email",
+	Role:    "role",
+	Balance: "balance",
+	Avatar:  "avatar",
+}
+
+// Having condition on grouped users
+func Having() *prisma.UserHaving {
+	return &prisma.UserHaving{}
+}
+
+// GroupOrder condition on grouped users
+func GroupOrder() *prisma.UserGroupOrder {
+	return &prisma.UserGroupOrder{}
+}
+
+// Distinct condition, returns one user for each distinct set of fields
+func Distinct(fields ...prisma.UserField) *prisma.UserDistinct {
+	c := prisma.UserDistinct(fields)
+	return &c
+}
+
 // Where condition
 func Where() *prisma.UserWhere {
 	return &prisma.UserWhere{}
 }
 
+// If requires the conditions to still hold when updating the user
+func If(conditions ...*prisma.UserWhere) *prisma.UserWhere {
+	return (&prisma.UserWhere{}).If(conditions...)
+}
+
 // Order condition
 func Order() *prisma.UserOrder {
 	return &prisma.UserOrder{}
@@ -40,17 +86,52 @@ func Order() *prisma.UserOrder {
 
 // First condition
 func First(first int) *prisma.UserFirst {
-	return &prisma.UserFirst{}
+	c := prisma.UserFirst(first)
+	return &c
 }
 
-// Select a user
-func Select(v interface{}) *prisma.UserSelect {
-	return &prisma.UserSelect{}
+// After condition
+func After(after string) *prisma.UserAfter {
+	c := prisma.UserAfter(after)
+	return &c
+}
+
+// Before condition
+func Before(before string) *prisma.UserBefore {
+	c := prisma.UserBefore(before)
+	return &c
+}
+
+// Skip condition
+func Skip(skip int) *prisma.UserSkip {
+	c := prisma.UserSkip(skip)
+	return &c
+}
+
+// Last condition
+func Last(last int) *prisma.UserLast {
+	c := prisma.UserLast(last)
+	return &c
 }
 
 // WithPosts conditions
 func WithPosts(conditions ...prisma.PostCondition) *prisma.UserWith {
-	return &prisma.UserWith{}
+	return (&prisma.UserWith{}).Posts(conditions...)
+}
+
+// WithComments includes the comments written by the user
+func WithComments(conditions ...prisma.CommentCondition) *prisma.UserWith {
+	return (&prisma.UserWith{}).Comments(conditions...)
+}
+
+// WithPostsCount filters the posts counted into PostsCount
+func WithPostsCount(where ...*prisma.PostWhere) *prisma.UserWith {
+	return (&prisma.UserWith{}).PostsCount(where...)
+}
+
+// WithCommentsCount filters the comments counted into CommentsCount
+func WithCommentsCount(where ...*prisma.CommentWhere) *prisma.UserWith {
+	return (&prisma.UserWith{}).CommentsCount(where...)
 }
 
 // // Input for a user
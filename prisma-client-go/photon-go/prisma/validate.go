@@ -0,0 +1,251 @@
+package prisma
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/prisma/specs/photongo/photon-go/prisma/id"
+)
+
+// FieldError is a field of an input that doesn't match the schema
+type FieldError struct {
+	// Field is the path of the field within the input, like
+	// posts.create[0].title for nested writes
+	Field   string
+	Message string
+}
+
+// Error implements error
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError lists every field of an input that doesn't match the
+// schema. It's returned before anything is sent to the engine.
+type ValidationError struct {
+	Model  string
+	Fields []*FieldError
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("prisma: invalid %s: %s", e.Model, strings.Join(msgs, ", "))
+}
+
+// rule is what the schema declares about a field of an input
+type rule struct {
+	field string
+	// required fields must be set on create and can't be set to null
+	required bool
+	// enum lists the values of an enum field
+	enum []string
+	// min and max limit the length of a string in characters, max is
+	// unlimited when zero
+	min, max int
+	pattern  *regexp.Regexp
+	// relation is the related model, for nested writes
+	relation string
+	// many relations hold a list of related records
+	many bool
+	// createdAt and updatedAt fields are timestamps filled in by the client
+	createdAt, updatedAt bool
+	// generate the field's value on create, for ids
+	generate func() string
+}
+
+// rules are the rules of a model's inputs
+type rules struct {
+	model  string
+	fields []rule
+	// unique are the fields that identify a record on their own, one of
+	// them is needed to connect a record
+	unique []string
+	// deletedAt is the field marked @deletedAt of soft deletable models
+	deletedAt string
+	// version is the field marked @version of versioned models
+	version string
+}
+
+var userRules = &rules{
+	model: "User",
+	fields: []rule{
+		{field: "id", generate: id.CUID},
+		{field: "name", max: 100},
+		{field: "email", required: true, pattern: regexp.MustCompile(`^[^@\s]+@[^@\s]+$`)},
+		{field: "role", enum: []string{string(UserRoleUser), string(UserRoleAdmin)}},
+		{field: "posts", relation: "Post", many: true},
+		{field: "comments", relation: "Comment", many: true},
+	},
+	unique: []string{"id", "email"},
+}
+
+var postRules = &rules{
+	model: "Post",
+	fields: []rule{
+		{field: "id", generate: id.CUID},
+		{field: "createdAt", createdAt: true},
+		{field: "updatedAt", updatedAt: true},
+		{field: "title", required: true, min: 1, max: 200},
+		{field: "author", relation: "User"},
+		{field: "comments", relation: "Comment", many: true},
+	},
+	unique:    []string{"id"},
+	deletedAt: "deletedAt",
+	version:   "version",
+}
+
+var commentRules = &rules{
+	model: "Comment",
+	fields: []rule{
+		{field: "id", generate: id.UUIDv4},
+		{field: "createdAt", createdAt: true},
+		{field: "text", required: true, min: 1, max: 2000},
+		{field: "post", required: true, relation: "Post"},
+		{field: "writtenBy", required: true, relation: "User"},
+	},
+	unique: []string{"id"},
+}
+
+// modelRules looks up the rules of related models
+var modelRules = map[string]*rules{
+	"User":    userRules,
+	"Post":    postRules,
+	"Comment": commentRules,
+}
+
+// relation returns the model of a relation field, or "" when the field
+// isn't a relation
+func (r *rules) relation(field string) string {
+	for _, rule := range r.fields {
+		if rule.field == field {
+			return rule.relation
+		}
+	}
+	return ""
+}
+
+// many returns the model of a to-many relation field, or "" when the field
+// isn't one
+func (r *rules) many(field string) string {
+	for _, rule := range r.fields {
+		if rule.field == field && rule.many {
+			return rule.relation
+		}
+	}
+	return ""
+}
+
+// validate data against the rules. Required fields are only checked when
+// creating.
+func (r *rules) validate(data object, create bool) error {
+	var errs []*FieldError
+	r.check(data, create, "", &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Model: r.model, Fields: errs}
+	}
+	return nil
+}
+
+// validateRows validates the rows of a bulk create, their fields are
+// prefixed by their index
+func (r *rules) validateRows(rows []object) error {
+	var errs []*FieldError
+	for i, row := range rows {
+		r.check(row, true, fmt.Sprintf("[%d].", i), &errs)
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Model: r.model, Fields: errs}
+	}
+	return nil
+}
+
+func (r *rules) check(data object, create bool, path string, errs *[]*FieldError) {
+	fail := func(name, format string, args ...interface{}) {
+		*errs = append(*errs, &FieldError{Field: path + name, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, rule := range r.fields {
+		value, ok := data.get(rule.field)
+		if !ok {
+			if create && rule.required {
+				fail(rule.field, "is required")
+			}
+			continue
+		}
+		if rule.relation != "" {
+			nested, _ := value.(object)
+			modelRules[rule.relation].checkRelation(rule, nested, path+rule.field, errs)
+			continue
+		}
+		switch v := value.(type) {
+		case nil:
+			if rule.required {
+				fail(rule.field, "can't be null")
+			}
+		case enum:
+			if !contains(rule.enum, v.enum()) {
+				fail(rule.field, "must be one of %s, not %q", strings.Join(rule.enum, ", "), v.enum())
+			}
+		case string:
+			n := utf8.RuneCountInString(v)
+			if n == 0 && rule.min > 0 {
+				fail(rule.field, "can't be empty")
+			} else if n < rule.min {
+				fail(rule.field, "must be at least %d characters", rule.min)
+			}
+			if rule.max > 0 && n > rule.max {
+				fail(rule.field, "must be at most %d characters", rule.max)
+			}
+			if rule.pattern != nil && !rule.pattern.MatchString(v) {
+				fail(rule.field, "must match %s", rule.pattern)
+			}
+		}
+	}
+}
+
+// checkRelation checks the nested writes of a relation to r's model
+func (r *rules) checkRelation(rule rule, nested object, path string, errs *[]*FieldError) {
+	for _, f := range nested {
+		var list []object
+		switch v := f.value.(type) {
+		case object:
+			list = []object{v}
+		case []object:
+			list = v
+		}
+		for i, item := range list {
+			prefix := path + "." + f.name
+			if _, many := f.value.([]object); many {
+				prefix += fmt.Sprintf("[%d]", i)
+			}
+			switch f.name {
+			case "create":
+				r.check(item, true, prefix+".", errs)
+			case "connect":
+				if len(pick(item, r.unique)) == 0 {
+					*errs = append(*errs, &FieldError{
+						Field:   prefix,
+						Message: "needs one of " + strings.Join(r.unique, ", "),
+					})
+				}
+			}
+		}
+		if f.name == "disconnect" && rule.required {
+			*errs = append(*errs, &FieldError{Field: path, Message: "can't be disconnected"})
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package prisma
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := (&UserInput{}).Email("ada@prisma.io")
+	tests := []struct {
+		name   string
+		rules  *rules
+		data   object
+		create bool
+		// err is the message of the ValidationError, empty when valid
+		err string
+	}{
+		{name: "valid", rules: userRules, data: valid.input(), create: true},
+		{name: "required", rules: userRules, data: (&UserInput{}).Name("Ada").input(), create: true, err: "prisma: invalid User: email is required"},
+		{name: "required on update", rules: userRules, data: (&UserInput{}).Name("Ada").input()},
+		{name: "null", rules: postRules, data: object{{"title", nil}}, err: "prisma: invalid Post: title can't be null"},
+		{name: "pattern", rules: userRules, data: (&UserInput{}).Email("ada").input(), err: `prisma: invalid User: email must match ^[^@\s]+@[^@\s]+$`},
+		{name: "enum", rules: userRules, data: valid.Role("OWNER").input(), err: `prisma: invalid User: role must be one of USER, ADMIN, not "OWNER"`},
+		{name: "empty", rules: postRules, data: (&PostInput{}).Title("").input(), err: "prisma: invalid Post: title can't be empty"},
+		{name: "too long", rules: userRules, data: valid.Name(strings.Repeat("a", 101)).input(), err: "prisma: invalid User: name must be at most 100 characters"},
+		// lengths are counted in characters, not bytes
+		{name: "characters", rules: userRules, data: valid.Name(strings.Repeat("é", 100)).input(), create: true},
+		{
+			name:   "every field",
+			rules:  userRules,
+			data:   (&UserInput{}).Name(strings.Repeat("a", 101)).Role("OWNER").input(),
+			create: true,
+			err:    `prisma: invalid User: name must be at most 100 characters, email is required, role must be one of USER, ADMIN, not "OWNER"`,
+		},
+		{
+			name:   "nested create",
+			rules:  userRules,
+			data:   valid.CreatePosts((&PostInput{}).Title("a"), (&PostInput{}).Title("")).input(),
+			create: true,
+			err:    "prisma: invalid User: posts.create[1].title can't be empty",
+		},
+		{
+			name:  "nested create on update",
+			rules: userRules,
+			// nested creates are creates, even within an update
+			data: (&UserInput{}).CreatePosts(&PostInput{}).input(),
+			err:  "prisma: invalid User: posts.create[0].title is required",
+		},
+		{
+			name:  "connect without a unique field",
+			rules: commentRules,
+			data:  (&CommentInput{}).ConnectPost(&PostConnect{}).input(),
+			err:   "prisma: invalid Comment: post.connect needs one of id",
+		},
+		{
+			name:  "disconnect required",
+			rules: commentRules,
+			data:  object{{"writtenBy", object{{"disconnect", true}}}},
+			err:   "prisma: invalid Comment: writtenBy can't be disconnected",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.rules.validate(test.data, test.create)
+			if test.err == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.err {
+				t.Fatalf("expected %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	client, db := testClient(nil)
+	_, err := client.User.Create((&UserInput{}).Email("ada").CreatePosts(&PostInput{}))
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	var fields []string
+	for _, f := range verr.Fields {
+		fields = append(fields, f.Field+": "+f.Message)
+	}
+	want := []string{`email: must match ^[^@\s]+@[^@\s]+$`, "posts.create[0].title: is required"}
+	if verr.Model != "User" || strings.Join(fields, "; ") != strings.Join(want, "; ") {
+		t.Fatalf("expected %v on User, got %v on %s", want, fields, verr.Model)
+	}
+	if len(db.sent()) != 0 {
+		t.Fatalf("expected nothing to be sent, got %v", db.sent())
+	}
+}
+
+func TestValidateRows(t *testing.T) {
+	client, db := testClient(nil)
+	users := []*UserInput{
+		(&UserInput{}).Email("ada@prisma.io"),
+		(&UserInput{}).Name("Grace"),
+		(&UserInput{}).Email("linus"),
+	}
+	_, err := client.User.CreateMany(client.Context(), users, nil)
+	want := "prisma: invalid User: [1].email is required, [2].email must match ^[^@\\s]+@[^@\\s]+$"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+	if len(db.sent()) != 0 {
+		t.Fatalf("expected nothing to be sent, got %v", db.sent())
+	}
+}
@@ -14,7 +14,7 @@ import (
 )
 
 // New API
-func New(env *env.Env, log log.Interface, pg *pgx.Conn) http.Handler {
+func New(env *env.Env, log log.Interface, pg *pgx.ConnPool) http.Handler {
 	handler := middleware.Compose(middleware.Log(log))
 	router := pat.New()
 
@@ -22,13 +22,17 @@ func New(env *env.Env, log log.Interface, pg *pgx.Conn) http.Handler {
 	users := users.New(log, pg)
 	router.Get("/users", http.HandlerFunc(users.Index))
 	router.Post("/users", http.HandlerFunc(users.Create))
+	router.Get("/users/:id", http.HandlerFunc(users.Show))
 	router.Post("/users/:id", http.HandlerFunc(users.Edit))
+	router.Del("/users/:id", http.HandlerFunc(users.Delete))
 
 	// posts
 	posts := posts.New(log, pg)
 	router.Get("/posts", http.HandlerFunc(posts.Index))
 	router.Post("/posts", http.HandlerFunc(posts.Create))
+	router.Get("/posts/:id", http.HandlerFunc(posts.Show))
 	router.Post("/posts/:id", http.HandlerFunc(posts.Edit))
+	router.Del("/posts/:id", http.HandlerFunc(posts.Delete))
 
 	return handler(router)
 }
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apex/log/handlers/memory"
+	"github.com/jackc/pgx/pgtype"
+	"github.com/prisma/specs/photongo/rest/internal/env"
+	"github.com/prisma/specs/photongo/rest/internal/logs"
+	"github.com/prisma/specs/photongo/rest/internal/pgtest"
+)
+
+var (
+	postColumns = []pgtest.Column{
+		{Name: "id", Type: pgtype.Int8OID},
+		{Name: "title", Type: pgtype.TextOID},
+		{Name: "published", Type: pgtype.BoolOID},
+		{Name: "author_id", Type: pgtype.Int8OID},
+	}
+	userColumns = []pgtest.Column{
+		{Name: "id", Type: pgtype.Int8OID},
+		{Name: "name", Type: pgtype.TextOID},
+		{Name: "email", Type: pgtype.TextOID},
+	}
+)
+
+// database answers the queries of the tests. Post 1 and user 1 exist, the
+// others don't.
+func database(query string) pgtest.Result {
+	switch {
+	case strings.HasPrefix(query, "DELETE"):
+		if strings.HasSuffix(query, "id = 1") {
+			return pgtest.Result{Tag: "DELETE 1"}
+		}
+		return pgtest.Result{Tag: "DELETE 0"}
+	case strings.Contains(query, "author_id) VALUES ('Hello', COALESCE(null, false), 2)"):
+		return pgtest.Result{Code: "23503"}
+	case strings.HasPrefix(query, "INSERT INTO posts"):
+		return pgtest.Result{Columns: postColumns, Rows: [][]interface{}{{7, "Hello", false, 1}}}
+	case strings.HasPrefix(query, "UPDATE posts"):
+		return pgtest.Result{Columns: postColumns, Rows: [][]interface{}{{1, "Hello", true, nil}}}
+	case strings.HasPrefix(query, "INSERT INTO users"):
+		return pgtest.Result{Columns: userColumns, Rows: [][]interface{}{{8, "Alice", "alice@prisma.io"}}}
+	case strings.HasPrefix(query, "UPDATE users"):
+		return pgtest.Result{Columns: userColumns, Rows: [][]interface{}{{1, nil, "alice@prisma.io"}}}
+	case strings.HasSuffix(query, "WHERE id = 1"):
+		return pgtest.Result{Columns: userColumns, Rows: [][]interface{}{{1, "Alice", "alice@prisma.io"}}}
+	}
+	return pgtest.Result{Columns: userColumns}
+}
+
+// api is the API on a fake database
+type api struct {
+	http.Handler
+	db     *pgtest.Server
+	logged *memory.Handler
+	close  func()
+}
+
+// serve starts the API on a fake database
+func serve(t *testing.T) *api {
+	t.Helper()
+	db, err := pgtest.NewServer(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pg, err := db.Pool()
+	if err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	logged := memory.New()
+	return &api{
+		Handler: New(&env.Env{}, logs.Info(logged), pg),
+		db:      db,
+		logged:  logged,
+		close: func() {
+			pg.Close()
+			db.Close()
+		},
+	}
+}
+
+// do sends a request with a JSON body to the API
+func do(api http.Handler, method, url, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, url, strings.NewReader(body))
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	api.ServeHTTP(w, r)
+	return w
+}
+
+// expect checks the status and the JSON body of a response
+func expect(t *testing.T, w *httptest.ResponseRecorder, status int, body string) {
+	t.Helper()
+	if w.Code != status || strings.TrimSpace(w.Body.String()) != body {
+		t.Fatalf("expected %d %s, got %d %s", status, body, w.Code, w.Body)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	api := serve(t)
+	defer api.close()
+	w := do(api, "POST", "/posts", `{"title": "Hello", "authorId": 1}`)
+	expect(t, w, http.StatusCreated, `{"id":7,"title":"Hello","published":false,"authorId":1}`)
+	if location := w.Header().Get("Location"); location != "/posts/7" {
+		t.Fatalf("expected the post's URL, got %q", location)
+	}
+	w = do(api, "POST", "/users", `{"name": "Alice", "email": "alice@prisma.io"}`)
+	expect(t, w, http.StatusCreated, `{"id":8,"name":"Alice","email":"alice@prisma.io"}`)
+	if location := w.Header().Get("Location"); location != "/users/8" {
+		t.Fatalf("expected the user's URL, got %q", location)
+	}
+	queries := api.db.Queries()
+	if len(queries) != 2 || !strings.Contains(queries[1], "VALUES ('Alice', 'alice@prisma.io')") {
+		t.Fatalf("expected the user to be inserted, got %v", queries)
+	}
+}
+
+func TestCreateInvalid(t *testing.T) {
+	api := serve(t)
+	defer api.close()
+	expect(t, do(api, "POST", "/posts", `{"published": true}`), http.StatusUnprocessableEntity, `{"error":"title is required"}`)
+	expect(t, do(api, "POST", "/posts", `{"title": ""}`), http.StatusUnprocessableEntity, `{"error":"title must be 1 to 200 characters"}`)
+	expect(t, do(api, "POST", "/users", `{"email": "alice"}`), http.StatusUnprocessableEntity, `{"error":"email is invalid"}`)
+	expect(t, do(api, "POST", "/users", `{"email": "alice@prisma.io", "admin": true}`), http.StatusBadRequest, `{"error":"invalid request body: json: unknown field \"admin\""}`)
+	if len(api.db.Queries()) != 0 {
+		t.Fatalf("expected nothing to be sent, got %v", api.db.Queries())
+	}
+	// the database checks the author
+	expect(t, do(api, "POST", "/posts", `{"title": "Hello", "authorId": 2}`), http.StatusUnprocessableEntity, `{"error":"author doesn't exist"}`)
+}
+
+func TestEdit(t *testing.T) {
+	api := serve(t)
+	defer api.close()
+	// null clears the author, fields that are left out are kept
+	w := do(api, "POST", "/posts/1", `{"published": true, "authorId": null}`)
+	expect(t, w, http.StatusOK, `{"id":1,"title":"Hello","published":true,"authorId":null}`)
+	w = do(api, "POST", "/users/1", `{"name": null}`)
+	expect(t, w, http.StatusOK, `{"id":1,"name":null,"email":"alice@prisma.io"}`)
+	queries := api.db.Queries()
+	for _, want := range []string{
+		"title = COALESCE(null, title)",
+		"published = COALESCE(true, published)",
+		"author_id = CASE WHEN true::boolean THEN null::bigint ELSE author_id END",
+	} {
+		if !strings.Contains(queries[0], want) {
+			t.Fatalf("expected %s in %s", want, queries[0])
+		}
+	}
+	for _, want := range []string{
+		"name = CASE WHEN true::boolean THEN null::text ELSE name END",
+		"email = COALESCE(null, email)",
+	} {
+		if !strings.Contains(queries[1], want) {
+			t.Fatalf("expected %s in %s", want, queries[1])
+		}
+	}
+	// required fields can't be cleared
+	expect(t, do(api, "POST", "/users/1", `{"email": null}`), http.StatusUnprocessableEntity, `{"error":"email is required"}`)
+	expect(t, do(api, "POST", "/posts/1", `{"title": null}`), http.StatusUnprocessableEntity, `{"error":"title is required"}`)
+}
+
+func TestEditKeepsAuthor(t *testing.T) {
+	api := serve(t)
+	defer api.close()
+	do(api, "POST", "/posts/1", `{"title": "Hi"}`)
+	query := api.db.Queries()[0]
+	if !strings.Contains(query, "title = COALESCE('Hi', title)") || !strings.Contains(query, "CASE WHEN false::boolean") {
+		t.Fatalf("expected only the title to change in %s", query)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	api := serve(t)
+	defer api.close()
+	expect(t, do(api, "DELETE", "/posts/1", ""), http.StatusNoContent, "")
+	expect(t, do(api, "DELETE", "/users/1", ""), http.StatusNoContent, "")
+	expect(t, do(api, "DELETE", "/posts/2", ""), http.StatusNotFound, `{"error":"post not found"}`)
+	expect(t, do(api, "DELETE", "/users/2", ""), http.StatusNotFound, `{"error":"user not found"}`)
+	// a malformed id can't match, so it isn't sent
+	expect(t, do(api, "DELETE", "/users/abc", ""), http.StatusNotFound, `{"error":"user not found"}`)
+	if len(api.db.Queries()) != 4 {
+		t.Fatalf("expected 4 deletes, got %v", api.db.Queries())
+	}
+}
+
+func TestShow(t *testing.T) {
+	api := serve(t)
+	defer api.close()
+	expect(t, do(api, "GET", "/users/1", ""), http.StatusOK, `{"id":1,"name":"Alice","email":"alice@prisma.io"}`)
+	expect(t, do(api, "GET", "/users/2", ""), http.StatusNotFound, `{"error":"user not found"}`)
+	expect(t, do(api, "GET", "/users", ""), http.StatusOK, `[]`)
+	for _, entry := range api.logged.Entries {
+		if entry.Level >= 3 {
+			t.Fatalf("expected client errors not to be logged, got %q", entry.Message)
+		}
+	}
+}
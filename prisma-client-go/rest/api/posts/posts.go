@@ -2,33 +2,200 @@ package posts
 
 import (
 	"net/http"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/apex/log"
 	"github.com/jackc/pgx"
+	"github.com/prisma/specs/photongo/rest/internal/httpjson"
+	"github.com/prisma/specs/photongo/rest/internal/postgres"
 )
 
 // New posts API
-func New(log log.Interface, pg *pgx.Conn) *Controller {
+func New(log log.Interface, pg *pgx.ConnPool) *Controller {
 	return &Controller{log, pg}
 }
 
 // Controller for posts
 type Controller struct {
 	log log.Interface
-	pg  *pgx.Conn
+	pg  *pgx.ConnPool
+}
+
+// Post is the JSON representation of a post
+type Post struct {
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	Published bool   `json:"published"`
+	AuthorID  *int64 `json:"authorId"`
+}
+
+// input is the body of Create and Edit, fields left out of an edit are kept
+// and fields sent as null are cleared
+type input struct {
+	Title     httpjson.String `json:"title"`
+	Published httpjson.Bool   `json:"published"`
+	AuthorID  httpjson.Int64  `json:"authorId"`
+}
+
+// validate the input, create requires a title. Only the author can be
+// cleared.
+func (in *input) validate(create bool) error {
+	if (create && in.Title.Value == nil) || in.Title.Null() {
+		return httpjson.Errorf(http.StatusUnprocessableEntity, "title is required")
+	}
+	if in.Published.Null() {
+		return httpjson.Errorf(http.StatusUnprocessableEntity, "published can't be null")
+	}
+	if in.Title.Value != nil {
+		if n := utf8.RuneCountInString(*in.Title.Value); n == 0 || n > 200 {
+			return httpjson.Errorf(http.StatusUnprocessableEntity, "title must be 1 to 200 characters")
+		}
+	}
+	return nil
+}
+
+const columns = `id, title, published, author_id`
+
+// scanner is a row or the current row of rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan a post from a row
+func scan(row scanner, p *Post) error {
+	return row.Scan(&p.ID, &p.Title, &p.Published, &p.AuthorID)
 }
 
 // Index lists all the posts
 func (c *Controller) Index(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("listing posts"))
+	rows, err := c.pg.Query(`SELECT ` + columns + ` FROM posts ORDER BY id`)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	defer rows.Close()
+	posts := []*Post{}
+	for rows.Next() {
+		var p Post
+		if err := scan(rows, &p); err != nil {
+			c.fail(w, err)
+			return
+		}
+		posts = append(posts, &p)
+	}
+	if err := rows.Err(); err != nil {
+		c.fail(w, err)
+		return
+	}
+	httpjson.Write(w, http.StatusOK, posts)
+}
+
+// Show a post
+func (c *Controller) Show(w http.ResponseWriter, r *http.Request) {
+	id, err := postID(r)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	var p Post
+	row := c.pg.QueryRow(`SELECT `+columns+` FROM posts WHERE id = $1`, id)
+	if err := scan(row, &p); err != nil {
+		c.fail(w, err)
+		return
+	}
+	httpjson.Write(w, http.StatusOK, &p)
 }
 
-// Create posts
+// Create a post
 func (c *Controller) Create(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("creating posts"))
+	var in input
+	if err := httpjson.Read(w, r, &in); err != nil {
+		c.fail(w, err)
+		return
+	}
+	if err := in.validate(true); err != nil {
+		c.fail(w, err)
+		return
+	}
+	var p Post
+	row := c.pg.QueryRow(`INSERT INTO posts (title, published, author_id) VALUES ($1, COALESCE($2, false), $3) RETURNING `+columns, in.Title.Value, in.Published.Value, in.AuthorID.Value)
+	if err := scan(row, &p); err != nil {
+		c.fail(w, err)
+		return
+	}
+	w.Header().Set("Location", "/posts/"+strconv.FormatInt(p.ID, 10))
+	httpjson.Write(w, http.StatusCreated, &p)
 }
 
 // Edit a post
 func (c *Controller) Edit(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("editing posts"))
+	id, err := postID(r)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	var in input
+	if err := httpjson.Read(w, r, &in); err != nil {
+		c.fail(w, err)
+		return
+	}
+	if err := in.validate(false); err != nil {
+		c.fail(w, err)
+		return
+	}
+	var p Post
+	row := c.pg.QueryRow(`UPDATE posts SET
+		title = COALESCE($2, title),
+		published = COALESCE($3, published),
+		author_id = CASE WHEN $4::boolean THEN $5::bigint ELSE author_id END
+		WHERE id = $1 RETURNING `+columns, id, in.Title.Value, in.Published.Value, in.AuthorID.Set, in.AuthorID.Value)
+	if err := scan(row, &p); err != nil {
+		c.fail(w, err)
+		return
+	}
+	httpjson.Write(w, http.StatusOK, &p)
+}
+
+// Delete a post
+func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := postID(r)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	tag, err := c.pg.Exec(`DELETE FROM posts WHERE id = $1`, id)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		c.fail(w, pgx.ErrNoRows)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// postID parses the id of the route, a malformed id can't match a post
+func postID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(r.URL.Query().Get(":id"), 10, 64)
+	if err != nil {
+		return 0, pgx.ErrNoRows
+	}
+	return id, nil
+}
+
+// fail responds with the error, unexpected errors are logged
+func (c *Controller) fail(w http.ResponseWriter, err error) {
+	switch {
+	case err == pgx.ErrNoRows:
+		err = httpjson.Errorf(http.StatusNotFound, "post not found")
+	case postgres.IsForeignKeyViolation(err):
+		err = httpjson.Errorf(http.StatusUnprocessableEntity, "author doesn't exist")
+	default:
+		if _, ok := err.(*httpjson.Error); !ok {
+			c.log.WithError(err).Error("posts")
+		}
+	}
+	httpjson.Fail(w, err)
 }
@@ -2,34 +2,193 @@ package users
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/jackc/pgx"
+	"github.com/prisma/specs/photongo/rest/internal/httpjson"
+	"github.com/prisma/specs/photongo/rest/internal/postgres"
 )
 
 // New users API
-func New(log log.Interface, pg *pgx.Conn) *Controller {
+func New(log log.Interface, pg *pgx.ConnPool) *Controller {
 	return &Controller{log, pg}
 }
 
 // Controller for users
 type Controller struct {
 	log log.Interface
-	pg  *pgx.Conn
+	pg  *pgx.ConnPool
+}
+
+// User is the JSON representation of a user
+type User struct {
+	ID    int64   `json:"id"`
+	Name  *string `json:"name"`
+	Email string  `json:"email"`
+}
+
+// input is the body of Create and Edit, fields left out of an edit are kept
+// and fields sent as null are cleared
+type input struct {
+	Name  httpjson.String `json:"name"`
+	Email httpjson.String `json:"email"`
+}
+
+// validate the input, create requires an email and it can't be cleared
+func (in *input) validate(create bool) error {
+	if (create && in.Email.Value == nil) || in.Email.Null() {
+		return httpjson.Errorf(http.StatusUnprocessableEntity, "email is required")
+	}
+	if in.Email.Value != nil && !strings.Contains(*in.Email.Value, "@") {
+		return httpjson.Errorf(http.StatusUnprocessableEntity, "email is invalid")
+	}
+	return nil
+}
+
+const columns = `id, name, email`
+
+// scanner is a row or the current row of rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan a user from a row
+func scan(row scanner, u *User) error {
+	return row.Scan(&u.ID, &u.Name, &u.Email)
 }
 
 // Index lists all the users
 func (c *Controller) Index(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("listing users"))
+	rows, err := c.pg.Query(`SELECT ` + columns + ` FROM users ORDER BY id`)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	defer rows.Close()
+	users := []*User{}
+	for rows.Next() {
+		var u User
+		if err := scan(rows, &u); err != nil {
+			c.fail(w, err)
+			return
+		}
+		users = append(users, &u)
+	}
+	if err := rows.Err(); err != nil {
+		c.fail(w, err)
+		return
+	}
+	httpjson.Write(w, http.StatusOK, users)
+}
+
+// Show a user
+func (c *Controller) Show(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	var u User
+	row := c.pg.QueryRow(`SELECT `+columns+` FROM users WHERE id = $1`, id)
+	if err := scan(row, &u); err != nil {
+		c.fail(w, err)
+		return
+	}
+	httpjson.Write(w, http.StatusOK, &u)
 }
 
-// Create a users
+// Create a user
 func (c *Controller) Create(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("creating users"))
+	var in input
+	if err := httpjson.Read(w, r, &in); err != nil {
+		c.fail(w, err)
+		return
+	}
+	if err := in.validate(true); err != nil {
+		c.fail(w, err)
+		return
+	}
+	var u User
+	row := c.pg.QueryRow(`INSERT INTO users (name, email) VALUES ($1, $2) RETURNING `+columns, in.Name.Value, in.Email.Value)
+	if err := scan(row, &u); err != nil {
+		c.fail(w, err)
+		return
+	}
+	w.Header().Set("Location", "/users/"+strconv.FormatInt(u.ID, 10))
+	httpjson.Write(w, http.StatusCreated, &u)
 }
 
-// Edit a post
+// Edit a user
 func (c *Controller) Edit(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get(":id")
-	w.Write([]byte("editing users: " + id))
+	id, err := userID(r)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	var in input
+	if err := httpjson.Read(w, r, &in); err != nil {
+		c.fail(w, err)
+		return
+	}
+	if err := in.validate(false); err != nil {
+		c.fail(w, err)
+		return
+	}
+	var u User
+	row := c.pg.QueryRow(`UPDATE users SET
+		name = CASE WHEN $2::boolean THEN $3::text ELSE name END,
+		email = COALESCE($4, email)
+		WHERE id = $1 RETURNING `+columns, id, in.Name.Set, in.Name.Value, in.Email.Value)
+	if err := scan(row, &u); err != nil {
+		c.fail(w, err)
+		return
+	}
+	httpjson.Write(w, http.StatusOK, &u)
+}
+
+// Delete a user
+func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	tag, err := c.pg.Exec(`DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		c.fail(w, err)
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		c.fail(w, pgx.ErrNoRows)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userID parses the id of the route, a malformed id can't match a user
+func userID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(r.URL.Query().Get(":id"), 10, 64)
+	if err != nil {
+		return 0, pgx.ErrNoRows
+	}
+	return id, nil
+}
+
+// fail responds with the error, unexpected errors are logged
+func (c *Controller) fail(w http.ResponseWriter, err error) {
+	switch {
+	case err == pgx.ErrNoRows:
+		err = httpjson.Errorf(http.StatusNotFound, "user not found")
+	case postgres.IsUniqueViolation(err):
+		err = httpjson.Errorf(http.StatusConflict, "email is already taken")
+	case postgres.IsForeignKeyViolation(err):
+		err = httpjson.Errorf(http.StatusConflict, "user still has posts")
+	default:
+		if _, ok := err.(*httpjson.Error); !ok {
+			c.log.WithError(err).Error("users")
+		}
+	}
+	httpjson.Fail(w, err)
 }
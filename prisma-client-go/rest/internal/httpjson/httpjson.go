@@ -0,0 +1,127 @@
+package httpjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// maxBody is the largest request body we'll read
+const maxBody = 1 << 20
+
+// Error is a client error with its status code
+type Error struct {
+	Status  int    `json:"-"`
+	Message string `json:"error"`
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errorf creates a client error
+func Errorf(status int, format string, args ...interface{}) *Error {
+	return &Error{Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+// tooLarge is the error of a body read past the limit of http.MaxBytesReader
+const tooLarge = "http: request body too large"
+
+// Read the JSON body of a request into v. The body must be a single JSON
+// value of at most 1MB, sent as application/json, without fields v doesn't
+// have. Otherwise the error is an *Error.
+func Read(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		return Errorf(http.StatusUnsupportedMediaType, "content type must be application/json")
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if err == io.EOF {
+			return Errorf(http.StatusBadRequest, "request body is empty")
+		}
+		// the reader's error is a *http.MaxBytesError since Go 1.19, its
+		// message is the same before
+		if err.Error() == tooLarge {
+			return Errorf(http.StatusRequestEntityTooLarge, "request body must be at most 1MB")
+		}
+		return Errorf(http.StatusBadRequest, "invalid request body: %s", err)
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return Errorf(http.StatusBadRequest, "request body must be a single JSON value")
+	}
+	return nil
+}
+
+// Write v as the JSON body of the response
+func Write(w http.ResponseWriter, status int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
+
+// Fail writes err as a JSON error. Client errors are written with their
+// status and message, any other error is an internal server error.
+func Fail(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = Errorf(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	Write(w, e.Status, e)
+}
+
+// String is an optional string field of a body. Set tells a field that was
+// left out apart from one sent as null, whose Value is nil.
+type String struct {
+	Set   bool
+	Value *string
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *String) UnmarshalJSON(b []byte) error {
+	s.Set = true
+	return json.Unmarshal(b, &s.Value)
+}
+
+// Null is true when the field was sent as null
+func (s String) Null() bool {
+	return s.Set && s.Value == nil
+}
+
+// Int64 is an optional integer field of a body, like String
+type Int64 struct {
+	Set   bool
+	Value *int64
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (i *Int64) UnmarshalJSON(b []byte) error {
+	i.Set = true
+	return json.Unmarshal(b, &i.Value)
+}
+
+// Null is true when the field was sent as null
+func (i Int64) Null() bool {
+	return i.Set && i.Value == nil
+}
+
+// Bool is an optional boolean field of a body, like String
+type Bool struct {
+	Set   bool
+	Value *bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	b.Set = true
+	return json.Unmarshal(data, &b.Value)
+}
+
+// Null is true when the field was sent as null
+func (b Bool) Null() bool {
+	return b.Set && b.Value == nil
+}
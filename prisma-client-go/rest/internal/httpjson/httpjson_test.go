@@ -0,0 +1,85 @@
+package httpjson
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type body struct {
+	Name  String `json:"name"`
+	Email String `json:"email"`
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		message     string
+	}{
+		{name: "valid", body: `{"name": "Alice"}`},
+		{name: "charset", contentType: "application/json; charset=utf-8", body: `{"name": "Alice"}`},
+		{name: "content type", contentType: "text/plain", body: `{"name": "Alice"}`, status: http.StatusUnsupportedMediaType, message: "content type must be application/json"},
+		{name: "empty", body: ``, status: http.StatusBadRequest, message: "request body is empty"},
+		{name: "unknown field", body: `{"name": "Alice", "admin": true}`, status: http.StatusBadRequest, message: `invalid request body: json: unknown field "admin"`},
+		{name: "malformed", body: `{"name": `, status: http.StatusBadRequest, message: "invalid request body: unexpected EOF"},
+		{name: "several values", body: `{"name": "Alice"} {}`, status: http.StatusBadRequest, message: "request body must be a single JSON value"},
+		{name: "too large", body: `{"name": "` + strings.Repeat("a", maxBody) + `"}`, status: http.StatusRequestEntityTooLarge, message: "request body must be at most 1MB"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(test.body))
+			r.Header.Set("Content-Type", "application/json")
+			if test.contentType != "" {
+				r.Header.Set("Content-Type", test.contentType)
+			}
+			var b body
+			err := Read(httptest.NewRecorder(), r, &b)
+			if test.status == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if b.Name.Value == nil || *b.Name.Value != "Alice" {
+					t.Fatalf("expected the name to be read, got %+v", b)
+				}
+				return
+			}
+			e, ok := err.(*Error)
+			if !ok || e.Status != test.status || e.Message != test.message {
+				t.Fatalf("expected %d %q, got %#v", test.status, test.message, err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	var b body
+	if err := json.Unmarshal([]byte(`{"name": null}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	if !b.Name.Null() {
+		t.Fatalf("expected the name to be null, got %+v", b.Name)
+	}
+	// a field that's left out isn't set, so it's kept
+	if b.Email.Set || b.Email.Null() {
+		t.Fatalf("expected the email not to be set, got %+v", b.Email)
+	}
+}
+
+func TestFail(t *testing.T) {
+	w := httptest.NewRecorder()
+	Fail(w, Errorf(http.StatusNotFound, "user not found"))
+	if w.Code != http.StatusNotFound || strings.TrimSpace(w.Body.String()) != `{"error":"user not found"}` {
+		t.Fatalf("expected a 404 with the message, got %d %s", w.Code, w.Body)
+	}
+	// other errors aren't shown to clients
+	w = httptest.NewRecorder()
+	Fail(w, &json.SyntaxError{})
+	if w.Code != http.StatusInternalServerError || strings.TrimSpace(w.Body.String()) != `{"error":"Internal Server Error"}` {
+		t.Fatalf("expected a 500, got %d %s", w.Code, w.Body)
+	}
+}
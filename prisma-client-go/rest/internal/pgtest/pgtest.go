@@ -0,0 +1,196 @@
+// Package pgtest runs a fake Postgres server for the tests of the API. Its
+// pools send queries with the simple protocol, so the server sees them with
+// their arguments in place and answers them with a Handler.
+package pgtest
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/jackc/pgx"
+	"github.com/jackc/pgx/pgproto3"
+	"github.com/jackc/pgx/pgtype"
+)
+
+// Column of a result, Type is its OID like pgtype.Int8OID
+type Column struct {
+	Name string
+	Type pgtype.OID
+}
+
+// Result answers a query
+type Result struct {
+	Columns []Column
+	// Rows hold a value for each column, nil for NULL
+	Rows [][]interface{}
+	// Tag is the command tag, like DELETE 1. It's SELECT and the number of
+	// rows when empty.
+	Tag string
+	// Code fails the query with an error of that SQLSTATE, like 23505
+	Code string
+}
+
+// Handler answers the queries sent to a Server
+type Handler func(query string) Result
+
+// Server is a fake Postgres server
+type Server struct {
+	ln      net.Listener
+	handler Handler
+
+	mu      sync.Mutex
+	queries []string
+}
+
+// NewServer starts a server answering queries with handler
+func NewServer(handler Handler) (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{ln: ln, handler: handler}
+	go s.accept()
+	return s, nil
+}
+
+// Pool opens a connection pool to the server
+func (s *Server) Pool() (*pgx.ConnPool, error) {
+	addr := s.ln.Addr().(*net.TCPAddr)
+	return pgx.NewConnPool(pgx.ConnPoolConfig{
+		ConnConfig: pgx.ConnConfig{
+			Host:                 addr.IP.String(),
+			Port:                 uint16(addr.Port),
+			User:                 "pgtest",
+			PreferSimpleProtocol: true,
+			// the server can't answer the queries of the types
+			CustomConnInfo: func(*pgx.Conn) (*pgtype.ConnInfo, error) {
+				info := pgtype.NewConnInfo()
+				info.InitializeDataTypes(map[string]pgtype.OID{
+					"bool": pgtype.BoolOID,
+					"int4": pgtype.Int4OID,
+					"int8": pgtype.Int8OID,
+					"text": pgtype.TextOID,
+				})
+				return info, nil
+			},
+		},
+		MaxConnections: 1,
+	})
+}
+
+// Queries returns the queries sent so far
+func (s *Server) Queries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.queries...)
+}
+
+// Close stops accepting connections
+func (s *Server) Close() error {
+	return s.ln.Close()
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.serve(conn)
+	}
+}
+
+// serve a connection until it's terminated
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	backend, err := pgproto3.NewBackend(conn, conn)
+	if err != nil {
+		return
+	}
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+	ready := &pgproto3.ReadyForQuery{TxStatus: 'I'}
+	err = send(backend,
+		&pgproto3.Authentication{Type: pgproto3.AuthTypeOk},
+		// pgx only sends queries with the simple protocol with these
+		&pgproto3.ParameterStatus{Name: "standard_conforming_strings", Value: "on"},
+		&pgproto3.ParameterStatus{Name: "client_encoding", Value: "UTF8"},
+		ready,
+	)
+	if err != nil {
+		return
+	}
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+		query, ok := msg.(*pgproto3.Query)
+		if !ok {
+			// terminated, or a message of the extended protocol
+			return
+		}
+		s.mu.Lock()
+		s.queries = append(s.queries, query.String)
+		s.mu.Unlock()
+		if err := send(backend, append(s.handler(query.String).messages(), ready)...); err != nil {
+			return
+		}
+	}
+}
+
+func send(backend *pgproto3.Backend, msgs ...pgproto3.BackendMessage) error {
+	for _, msg := range msgs {
+		if err := backend.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// messages answer a query with the result
+func (r Result) messages() []pgproto3.BackendMessage {
+	if r.Code != "" {
+		return []pgproto3.BackendMessage{&pgproto3.ErrorResponse{
+			Severity: "ERROR",
+			Code:     r.Code,
+			Message:  "pgtest: error " + r.Code,
+		}}
+	}
+	var msgs []pgproto3.BackendMessage
+	if len(r.Columns) > 0 {
+		fields := make([]pgproto3.FieldDescription, len(r.Columns))
+		for i, c := range r.Columns {
+			fields[i] = pgproto3.FieldDescription{Name: c.Name, DataTypeOID: uint32(c.Type), DataTypeSize: -1}
+		}
+		msgs = append(msgs, &pgproto3.RowDescription{Fields: fields})
+	}
+	for _, row := range r.Rows {
+		values := make([][]byte, len(row))
+		for i, v := range row {
+			values[i] = text(v)
+		}
+		msgs = append(msgs, &pgproto3.DataRow{Values: values})
+	}
+	tag := r.Tag
+	if tag == "" {
+		tag = fmt.Sprintf("SELECT %d", len(r.Rows))
+	}
+	return append(msgs, &pgproto3.CommandComplete{CommandTag: tag})
+}
+
+// text formats a value like Postgres does in the text format
+func text(v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case bool:
+		if v {
+			return []byte("t")
+		}
+		return []byte("f")
+	}
+	return []byte(fmt.Sprint(v))
+}
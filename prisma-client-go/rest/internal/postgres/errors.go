@@ -0,0 +1,26 @@
+package postgres
+
+import "github.com/jackc/pgx"
+
+// error codes of the constraint violations
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// IsUniqueViolation is true when err violates a unique constraint
+func IsUniqueViolation(err error) bool {
+	return code(err) == uniqueViolation
+}
+
+// IsForeignKeyViolation is true when err references a missing row
+func IsForeignKeyViolation(err error) bool {
+	return code(err) == foreignKeyViolation
+}
+
+func code(err error) string {
+	if pgErr, ok := err.(pgx.PgError); ok {
+		return pgErr.Code
+	}
+	return ""
+}
@@ -2,11 +2,12 @@ package postgres
 
 import "github.com/jackc/pgx"
 
-// Dial a postgres server
-func Dial(url string) (*pgx.Conn, error) {
+// Dial a pool of connections to a postgres server, so requests can query
+// it at the same time
+func Dial(url string) (*pgx.ConnPool, error) {
 	cfg, err := pgx.ParseConnectionString(url)
 	if err != nil {
 		return nil, err
 	}
-	return pgx.Connect(cfg)
+	return pgx.NewConnPool(pgx.ConnPoolConfig{ConnConfig: cfg})
 }
@@ -0,0 +1,12 @@
+CREATE TABLE users (
+  id bigserial PRIMARY KEY,
+  name text,
+  email text NOT NULL UNIQUE
+);
+
+CREATE TABLE posts (
+  id bigserial PRIMARY KEY,
+  title text NOT NULL,
+  published boolean NOT NULL DEFAULT false,
+  author_id bigint REFERENCES users (id)
+);